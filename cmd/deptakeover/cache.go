@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Swayamyadav01/Deptakeover/internal/cache"
+
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect or clear the registry response cache",
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show registry cache statistics",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := openCache(cache.Options{})
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		stats, err := c.Stats()
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("📁 Cache dir: %s\n", stats.Dir)
+		fmt.Printf("📦 Entries:   %d (%d found, %d not found)\n", stats.Entries, stats.Positive, stats.Negative)
+		fmt.Printf("⏰ Expired:   %d (default TTLs)\n", stats.Expired)
+		fmt.Printf("💾 Size:      %.1f KB\n", float64(stats.Bytes)/1024)
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete every cached registry response",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := openCache(cache.Options{})
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := c.Clear(); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("✅ Cache cleared")
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheStatsCmd, cacheClearCmd)
+}
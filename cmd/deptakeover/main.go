@@ -3,14 +3,16 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/Swayamyadav01/Deptakeover/internal/cache"
+	"github.com/Swayamyadav01/Deptakeover/internal/ecosystem"
 	"github.com/Swayamyadav01/Deptakeover/internal/github"
+	"github.com/Swayamyadav01/Deptakeover/internal/httpclient"
 	"github.com/Swayamyadav01/Deptakeover/internal/registry"
 	"github.com/Swayamyadav01/Deptakeover/internal/scanner"
 
@@ -26,18 +28,31 @@ type ReportData struct {
 }
 
 type EcosystemData struct {
-	DependenciesByFile map[string]interface{} `json:"dependencies_by_file"`
-	TotalDependencies  int                    `json:"total_dependencies"`
-	RiskAnalysis       map[string]interface{} `json:"risk_analysis"`
-	Summary            SummaryData            `json:"summary"`
+	DependenciesByFile map[string][]scanner.Dependency `json:"dependencies_by_file"`
+	TotalDependencies  int                             `json:"total_dependencies"`
+	RiskAnalysis       map[string]registry.PackageInfo `json:"risk_analysis"`
+	NamespaceAnalysis  map[string]registry.PackageInfo `json:"namespace_analysis,omitempty"`
+	RepoAnalysis       map[string]registry.PackageInfo `json:"repo_analysis,omitempty"`
+	PrivateRegistries  []scanner.PrivateRegistry       `json:"private_registries,omitempty"`
+	Summary            SummaryData                     `json:"summary"`
 }
 
 type SummaryData struct {
-	HighRiskCount    int      `json:"high_risk_count"`
-	MediumRiskCount  int      `json:"medium_risk_count"`
-	NotFoundCount    int      `json:"not_found_count"`
-	HighRiskPackages []string `json:"high_risk_packages"`
-	NotFoundPackages []string `json:"not_found_packages"`
+	UnclaimedNamespaceCount int      `json:"unclaimed_namespace_count"`
+	UnclaimedNamespaces     []string `json:"unclaimed_namespaces"`
+	ConfusionCount          int      `json:"dependency_confusion_count"`
+	ConfusionPackages       []string `json:"dependency_confusion_packages"`
+	ClaimableRepoCount      int      `json:"claimable_repo_count"`
+	ClaimableRepos          []string `json:"claimable_repos"`
+	StaleCount              int      `json:"stale_count"`
+	StalePackages           []string `json:"stale_packages"`
+	HighRiskCount           int      `json:"high_risk_count"`
+	MediumRiskCount         int      `json:"medium_risk_count"`
+	NotFoundCount           int      `json:"not_found_count"`
+	UnknownCount            int      `json:"unknown_count"`
+	HighRiskPackages        []string `json:"high_risk_packages"`
+	NotFoundPackages        []string `json:"not_found_packages"`
+	UnknownPackages         []string `json:"unknown_packages"`
 }
 
 var rootCmd = &cobra.Command{
@@ -102,35 +117,28 @@ PERFECT FOR:
 		ecosystemInput := args[0]
 		targetInput := args[1]
 
-		// Map ecosystem names
-		ecosystemMap := map[string]string{
-			"npm":          "npm",
-			"pypi":         "pypi",
-			"py":           "pypi",
-			"python":       "pypi",
-			"composer":     "composer",
-			"php":          "composer",
-			"org":          "org",
-			"org-npm":      "org-npm",
-			"org-pypi":     "org-pypi",
-			"org-composer": "org-composer",
-		}
-
-		ecosystem, exists := ecosystemMap[ecosystemInput]
-		if !exists {
-			fmt.Printf("Unknown ecosystem: '%s'\n", ecosystemInput)
-			fmt.Println("Valid options: npm, pypi, py, composer, php")
-			fmt.Println("Org scans: org, org-npm, org-pypi, org-composer")
-			fmt.Println("Example: deptakeover npm lodash/lodash")
-			os.Exit(1)
-		}
+		registry.SetConcurrency(concurrency)
+		registry.SetUnknownRetries(retryUnknown)
+		ecosystem.SetDepth(depth)
+		setupCache()
 
 		// Handle organization scanning
-		if strings.HasPrefix(ecosystem, "org") {
-			runOrgScan(ecosystem, targetInput)
+		if ecosystemInput == "org" || strings.HasPrefix(ecosystemInput, "org-") {
+			ecosystems, scanType, ok := getEcosystemsForOrgScan(ecosystemInput)
+			if !ok {
+				printUnknownEcosystem(ecosystemInput)
+				os.Exit(1)
+			}
+			runOrgScan(scanType, targetInput, ecosystems)
 			return
 		}
 
+		eco, exists := ecosystem.Get(ecosystemInput)
+		if !exists {
+			printUnknownEcosystem(ecosystemInput)
+			os.Exit(1)
+		}
+
 		// Handle single repository scanning
 		outFile := ecosystemInput + "_report.json"
 
@@ -142,10 +150,18 @@ PERFECT FOR:
 			githubRepo = targetInput
 		}
 
-		runScan(githubURL, githubRepo, "", "", ecosystem, outFile)
+		runScan(githubURL, githubRepo, "", "", eco, outFile)
 	},
 }
 
+func printUnknownEcosystem(input string) {
+	names := ecosystem.Names()
+	fmt.Printf("Unknown ecosystem: '%s'\n", input)
+	fmt.Printf("Valid options: %s\n", strings.Join(ecosystem.Aliases(), ", "))
+	fmt.Printf("Org scans: org, org-%s\n", strings.Join(names, ", org-"))
+	fmt.Println("Example: deptakeover npm lodash/lodash")
+}
+
 const bannerText = " ____           _____     _\n" +
 	"|  _ \\  ___ _ _|_   _|_ _| | _____  _____   _____ _ __ \n" +
 	"| | | |/ _ \\ '_ \\| |/ _` | |/ / _ \\/ _ \\ \\ / / _ \\ '__|\n" +
@@ -156,12 +172,65 @@ const bannerText = " ____           _____     _\n" +
 	"Find missing packages across npm, PyPI, and Composer\n" +
 	"Report unclaimed dependencies before attackers do\n"
 
+var (
+	concurrency      int
+	depth            int
+	retryUnknown     int
+	failOnUnknown    bool
+	noCache          bool
+	refreshCache     bool
+	cacheDir         string
+	cacheTTL         time.Duration
+	cacheNegativeTTL time.Duration
+)
+
 func init() {
-	// Positional arguments only - no flags
+	rootCmd.Args = cobra.ArbitraryArgs
+	rootCmd.AddCommand(cacheCmd)
+
+	rootCmd.Flags().IntVar(&concurrency, "concurrency", registry.DefaultConcurrency, "number of parallel registry lookups")
+	rootCmd.Flags().IntVar(&depth, "depth", 0, "also check dependencies of dependencies this many levels down, using registry metadata")
+	rootCmd.Flags().IntVar(&retryUnknown, "retry-unknown", 0, "extra lookup rounds for packages whose registry status is unknown")
+	rootCmd.Flags().BoolVar(&failOnUnknown, "fail-on-unknown", false, "exit with an error if any registry lookup could not be completed")
+	rootCmd.Flags().BoolVar(&noCache, "no-cache", false, "do not read or write the registry response cache")
+	rootCmd.Flags().BoolVar(&refreshCache, "refresh", false, "ignore cached registry responses but store fresh ones")
+	rootCmd.Flags().DurationVar(&cacheTTL, "cache-ttl", cache.DefaultPositiveTTL, "how long cached \"package exists\" answers stay valid")
+	rootCmd.Flags().DurationVar(&cacheNegativeTTL, "cache-negative-ttl", cache.DefaultNegativeTTL, "how long cached \"package not found\" answers stay valid")
+	rootCmd.PersistentFlags().StringVar(&cacheDir, "cache-dir", "", "registry cache directory (default: user cache dir)")
+}
+
+// setupCache opens the registry cache according to the command-line flags.
+// Failing to open it only disables caching.
+func setupCache() {
+	if noCache {
+		return
+	}
+
+	c, err := openCache(cache.Options{
+		PositiveTTL: cacheTTL,
+		NegativeTTL: cacheNegativeTTL,
+		Refresh:     refreshCache,
+	})
+	if err != nil {
+		fmt.Printf("⚠️  Registry cache disabled: %v\n", err)
+		return
+	}
+	ecosystem.SetCache(c)
 }
 
-func runScan(githubURL, githubRepo, githubOrg, localPath, ecosystem, outFile string) {
-	fmt.Printf("🔍 Scanning [%s]...\n", ecosystem)
+func openCache(opts cache.Options) (*cache.Cache, error) {
+	dir := cacheDir
+	if dir == "" {
+		var err error
+		if dir, err = cache.DefaultDir(); err != nil {
+			return nil, err
+		}
+	}
+	return cache.Open(dir, opts)
+}
+
+func runScan(githubURL, githubRepo, githubOrg, localPath string, eco ecosystem.Ecosystem, outFile string) {
+	fmt.Printf("🔍 Scanning [%s]...\n", eco.Name())
 
 	// Get repo path
 	repoPath, err := github.GetRepoPath(githubURL, githubRepo, githubOrg, localPath)
@@ -182,58 +251,20 @@ func runScan(githubURL, githubRepo, githubOrg, localPath, ecosystem, outFile str
 		report.GitHubURL = &githubURL
 	}
 
-	// Single ecosystem scan
-	if ecosystem == "npm" {
-		depsByFile := scanner.ExtractAllNPMDependencies(repoPath)
-		if len(depsByFile) > 0 {
-			allDeps := scanner.GetAllUniqueNPMDeps(repoPath)
-			fmt.Printf("📦 Found %d packages\n", len(allDeps))
+	scan := ecosystem.Scan(eco, repoPath)
+	if len(scan.DependenciesByFile) > 0 {
+		fmt.Printf("📦 Found %d packages\n", len(scan.Packages))
 
-			riskAnalysis := registry.AnalyzeNPMDependencyRisks(allDeps)
+		analysis := ecosystem.Analyze(eco, scan)
 
-			npm := EcosystemData{
-				DependenciesByFile: convertNPMDeps(depsByFile),
-				TotalDependencies:  len(allDeps),
-				RiskAnalysis:       convertNPMAnalysis(riskAnalysis),
-				Summary:            generateSummary(riskAnalysis),
-			}
-			report.Ecosystems["npm"] = npm
-		}
-	}
-
-	if ecosystem == "pypi" {
-		depsByFile := scanner.ExtractAllPythonDependencies(repoPath)
-		if len(depsByFile) > 0 {
-			allDeps := scanner.GetAllUniquePythonDeps(repoPath)
-			fmt.Printf("📦 Found %d packages\n", len(allDeps))
-
-			riskAnalysis := registry.AnalyzePyPIDependencyRisks(allDeps)
-
-			pypi := EcosystemData{
-				DependenciesByFile: convertPythonDeps(depsByFile),
-				TotalDependencies:  len(allDeps),
-				RiskAnalysis:       convertPyPIAnalysis(riskAnalysis),
-				Summary:            generatePyPISummary(riskAnalysis),
-			}
-			report.Ecosystems["pypi"] = pypi
-		}
-	}
-
-	if ecosystem == "composer" {
-		depsByFile := scanner.ExtractAllPHPDependencies(repoPath)
-		if len(depsByFile) > 0 {
-			allDeps := scanner.GetAllUniquePHPDeps(repoPath)
-			fmt.Printf("📦 Found %d packages\n", len(allDeps))
-
-			riskAnalysis := registry.AnalyzePackagistDependencyRisks(allDeps)
-
-			composer := EcosystemData{
-				DependenciesByFile: convertPHPDeps(depsByFile),
-				TotalDependencies:  len(allDeps),
-				RiskAnalysis:       convertPackagistAnalysis(riskAnalysis),
-				Summary:            generatePackagistSummary(riskAnalysis),
-			}
-			report.Ecosystems["composer"] = composer
+		report.Ecosystems[eco.Name()] = EcosystemData{
+			DependenciesByFile: scan.DependenciesByFile,
+			TotalDependencies:  len(scan.Packages),
+			RiskAnalysis:       analysis.Packages,
+			NamespaceAnalysis:  analysis.Namespaces,
+			RepoAnalysis:       analysis.Repos,
+			PrivateRegistries:  scan.PrivateRegistries,
+			Summary:            generateSummary(analysis),
 		}
 	}
 
@@ -249,6 +280,21 @@ func runScan(githubURL, githubRepo, githubOrg, localPath, ecosystem, outFile str
 
 	fmt.Printf("✅ Report: %s\n", outFile)
 	printSummary(report)
+
+	unknown := 0
+	for _, eco := range report.Ecosystems {
+		unknown += eco.Summary.UnknownCount
+	}
+	exitOnUnknown(unknown)
+}
+
+// exitOnUnknown aborts with a non-zero exit code when --fail-on-unknown is
+// set and some packages could not be checked.
+func exitOnUnknown(unknown int) {
+	if failOnUnknown && unknown > 0 {
+		fmt.Printf("❌ %d registry lookups failed (status unknown), results are incomplete\n", unknown)
+		os.Exit(1)
+	}
 }
 
 // GitHub API response structure for repository listing
@@ -269,19 +315,22 @@ type OrgReportData struct {
 	ScannedRepos       int                       `json:"scanned_repos"`
 	SkippedRepos       int                       `json:"skipped_repos"`
 	TotalVulns         int                       `json:"total_vulnerabilities"`
+	TotalUnknown       int                       `json:"total_unknown"`
 	RepositorySummary  map[string]RepoScanResult `json:"repository_summary"`
 	TopVulnerabilities []VulnSummary             `json:"top_vulnerabilities"`
 	ScanTimestamp      time.Time                 `json:"scan_timestamp"`
 }
 
 type RepoScanResult struct {
-	Language     string   `json:"language,omitempty"`
-	Stars        int      `json:"stars"`
-	Size         int      `json:"size_kb"`
-	VulnCount    int      `json:"vulnerability_count"`
-	VulnPackages []string `json:"vulnerable_packages"`
-	ScanStatus   string   `json:"scan_status"`
-	Error        string   `json:"error,omitempty"`
+	Language        string   `json:"language,omitempty"`
+	Stars           int      `json:"stars"`
+	Size            int      `json:"size_kb"`
+	VulnCount       int      `json:"vulnerability_count"`
+	VulnPackages    []string `json:"vulnerable_packages"`
+	UnknownCount    int      `json:"unknown_count"`
+	UnknownPackages []string `json:"unknown_packages,omitempty"`
+	ScanStatus      string   `json:"scan_status"`
+	Error           string   `json:"error,omitempty"`
 }
 
 type VulnSummary struct {
@@ -291,7 +340,7 @@ type VulnSummary struct {
 	Frequency    int      `json:"frequency"`
 }
 
-func runOrgScan(scanType, orgName string) {
+func runOrgScan(scanType, orgName string, ecosystems []ecosystem.Ecosystem) {
 	fmt.Printf("🔍 Organization Scan: %s [%s]\n", orgName, scanType)
 
 	// Get repositories from GitHub API
@@ -313,9 +362,6 @@ func runOrgScan(scanType, orgName string) {
 
 	vulnMap := make(map[string]*VulnSummary)
 
-	// Determine which ecosystems to scan
-	ecosystems := getEcosystemsForOrgScan(scanType)
-
 	count := 0
 	for _, repo := range repos {
 		count++
@@ -342,14 +388,20 @@ func runOrgScan(scanType, orgName string) {
 		}
 
 		// Run scans for each ecosystem
-		for _, eco := range ecosystems {
-			vulnPackages, err := scanRepoForEcosystem(repo.FullName, eco)
+		for _, e := range ecosystems {
+			eco := e.Name()
+			vulnPackages, unknownPackages, err := scanRepoForEcosystem(repo.FullName, e)
 			if err != nil {
 				repoResult.Error = err.Error()
 				repoResult.ScanStatus = "error"
 				continue
 			}
 
+			for _, pkg := range unknownPackages {
+				repoResult.UnknownPackages = append(repoResult.UnknownPackages, fmt.Sprintf("%s:%s", eco, pkg))
+				repoResult.UnknownCount++
+			}
+
 			// Collect vulnerabilities
 			for _, pkg := range vulnPackages {
 				repoResult.VulnPackages = append(repoResult.VulnPackages, fmt.Sprintf("%s:%s", eco, pkg))
@@ -374,6 +426,7 @@ func runOrgScan(scanType, orgName string) {
 		report.RepositorySummary[repo.Name] = repoResult
 		report.ScannedRepos++
 		report.TotalVulns += repoResult.VulnCount
+		report.TotalUnknown += repoResult.UnknownCount
 
 		// Rate limiting - pause between repos
 		time.Sleep(500 * time.Millisecond)
@@ -386,7 +439,14 @@ func runOrgScan(scanType, orgName string) {
 
 	// Sort by frequency (most common vulnerabilities first)
 	sort.Slice(report.TopVulnerabilities, func(i, j int) bool {
-		return report.TopVulnerabilities[i].Frequency > report.TopVulnerabilities[j].Frequency
+		a, b := report.TopVulnerabilities[i], report.TopVulnerabilities[j]
+		if a.Frequency != b.Frequency {
+			return a.Frequency > b.Frequency
+		}
+		if a.Ecosystem != b.Ecosystem {
+			return a.Ecosystem < b.Ecosystem
+		}
+		return a.PackageName < b.PackageName
 	})
 
 	// Save organization report
@@ -397,6 +457,7 @@ func runOrgScan(scanType, orgName string) {
 
 	// Print summary
 	printOrgSummary(report)
+	exitOnUnknown(report.TotalUnknown)
 }
 
 func getOrgRepositories(orgName string) ([]GitHubRepo, error) {
@@ -406,21 +467,24 @@ func getOrgRepositories(orgName string) ([]GitHubRepo, error) {
 
 	for {
 		pageURL := fmt.Sprintf("%s&page=%d", url, page)
-		resp, err := http.Get(pageURL)
+		resp, err := httpclient.Default.Get(pageURL)
 		if err != nil {
 			return nil, fmt.Errorf("API request failed: %w", err)
 		}
-		defer resp.Body.Close()
 
 		if resp.StatusCode == 404 {
+			resp.Body.Close()
 			return nil, fmt.Errorf("organization '%s' not found", orgName)
 		}
 		if resp.StatusCode != 200 {
+			resp.Body.Close()
 			return nil, fmt.Errorf("API error: %d", resp.StatusCode)
 		}
 
 		var repos []GitHubRepo
-		if err := json.NewDecoder(resp.Body).Decode(&repos); err != nil {
+		err = json.NewDecoder(resp.Body).Decode(&repos)
+		resp.Body.Close()
+		if err != nil {
 			return nil, fmt.Errorf("JSON decode error: %w", err)
 		}
 
@@ -438,67 +502,61 @@ func getOrgRepositories(orgName string) ([]GitHubRepo, error) {
 	return allRepos, nil
 }
 
-func getEcosystemsForOrgScan(scanType string) []string {
-	switch scanType {
-	case "org":
-		return []string{"npm", "pypi", "composer"}
-	case "org-npm":
-		return []string{"npm"}
-	case "org-pypi":
-		return []string{"pypi"}
-	case "org-composer":
-		return []string{"composer"}
-	default:
-		return []string{"npm", "pypi", "composer"}
+// getEcosystemsForOrgScan resolves an org scan type ("org" or "org-<ecosystem>")
+// to the ecosystems it covers and its canonical scan type name.
+func getEcosystemsForOrgScan(scanType string) ([]ecosystem.Ecosystem, string, bool) {
+	if scanType == "org" {
+		var ecosystems []ecosystem.Ecosystem
+		for _, name := range ecosystem.Names() {
+			eco, _ := ecosystem.Get(name)
+			ecosystems = append(ecosystems, eco)
+		}
+		return ecosystems, scanType, true
+	}
+
+	eco, exists := ecosystem.Get(strings.TrimPrefix(scanType, "org-"))
+	if !exists {
+		return nil, "", false
 	}
+	return []ecosystem.Ecosystem{eco}, "org-" + eco.Name(), true
 }
 
-func scanRepoForEcosystem(repoFullName, ecosystem string) ([]string, error) {
+// scanRepoForEcosystem returns the vulnerable packages of a repository and
+// the packages whose registry status could not be determined.
+func scanRepoForEcosystem(repoFullName string, eco ecosystem.Ecosystem) ([]string, []string, error) {
 	// Clone or use cached repo
 	repoPath, err := github.GetRepoPath("", repoFullName, "", "")
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	var vulnerablePackages []string
+	var vulnerablePackages, unknownPackages []string
 
-	switch ecosystem {
-	case "npm":
-		depsByFile := scanner.ExtractAllNPMDependencies(repoPath)
-		if len(depsByFile) > 0 {
-			allDeps := scanner.GetAllUniqueNPMDeps(repoPath)
-			riskAnalysis := registry.AnalyzeNPMDependencyRisks(allDeps)
-			for pkg, risk := range riskAnalysis {
-				if risk.RiskScore >= 70 || !risk.Exists {
-					vulnerablePackages = append(vulnerablePackages, pkg)
-				}
+	scan := ecosystem.Scan(eco, repoPath)
+	if len(scan.DependenciesByFile) > 0 {
+		analysis := ecosystem.Analyze(eco, scan)
+		for pkg, risk := range analysis.Packages {
+			if risk.Status == registry.StatusUnknown {
+				unknownPackages = append(unknownPackages, pkg)
+			} else if risk.RiskScore >= 70 || risk.Status == registry.StatusNotFound {
+				vulnerablePackages = append(vulnerablePackages, pkg)
 			}
 		}
-	case "pypi":
-		depsByFile := scanner.ExtractAllPythonDependencies(repoPath)
-		if len(depsByFile) > 0 {
-			allDeps := scanner.GetAllUniquePythonDeps(repoPath)
-			riskAnalysis := registry.AnalyzePyPIDependencyRisks(allDeps)
-			for pkg, risk := range riskAnalysis {
-				if risk.RiskScore >= 70 || !risk.Exists {
-					vulnerablePackages = append(vulnerablePackages, pkg)
-				}
+		for ns, info := range analysis.Namespaces {
+			if info.Status == registry.StatusNotFound {
+				vulnerablePackages = append(vulnerablePackages, ns)
 			}
 		}
-	case "composer":
-		depsByFile := scanner.ExtractAllPHPDependencies(repoPath)
-		if len(depsByFile) > 0 {
-			allDeps := scanner.GetAllUniquePHPDeps(repoPath)
-			riskAnalysis := registry.AnalyzePackagistDependencyRisks(allDeps)
-			for pkg, risk := range riskAnalysis {
-				if risk.RiskScore >= 70 || !risk.Exists {
-					vulnerablePackages = append(vulnerablePackages, pkg)
-				}
+		for repo, info := range analysis.Repos {
+			if info.Severity == registry.SeverityCritical {
+				vulnerablePackages = append(vulnerablePackages, "github.com/"+repo)
 			}
 		}
 	}
 
-	return vulnerablePackages, nil
+	sort.Strings(vulnerablePackages)
+	sort.Strings(unknownPackages)
+	return vulnerablePackages, unknownPackages, nil
 }
 
 func printOrgSummary(report OrgReportData) {
@@ -510,6 +568,9 @@ func printOrgSummary(report OrgReportData) {
 	fmt.Printf("📁 Repositories: %d total, %d scanned, %d skipped\n",
 		report.TotalRepos, report.ScannedRepos, report.SkippedRepos)
 	fmt.Printf("🚨 Total Vulnerabilities: %d\n", report.TotalVulns)
+	if report.TotalUnknown > 0 {
+		fmt.Printf("❓ Unknown (lookup failed): %d\n", report.TotalUnknown)
+	}
 
 	if len(report.TopVulnerabilities) > 0 {
 		fmt.Printf("\n🔥 TOP VULNERABLE PACKAGES:\n")
@@ -565,151 +626,77 @@ func printOrgSummary(report OrgReportData) {
 	fmt.Println(strings.Repeat("═", 60))
 }
 
-func generateSummary(riskAnalysis map[string]registry.NPMPackageInfo) SummaryData {
-	var highRisk, mediumRisk, notFound []string
+func generateSummary(analysis ecosystem.Analysis) SummaryData {
+	var unclaimedNamespaces, confusion, claimableRepos, stale, highRisk, mediumRisk, notFound, unknown []string
 
-	for pkg, risk := range riskAnalysis {
-		if risk.RiskScore >= 70 {
-			highRisk = append(highRisk, pkg)
-		} else if risk.RiskScore >= 40 {
-			mediumRisk = append(mediumRisk, pkg)
-		} else if !risk.Exists {
-			notFound = append(notFound, pkg)
+	for ns, info := range analysis.Namespaces {
+		if info.Status == registry.StatusNotFound {
+			unclaimedNamespaces = append(unclaimedNamespaces, ns)
 		}
 	}
 
-	sort.Strings(highRisk)
-	sort.Strings(mediumRisk)
-	sort.Strings(notFound)
-
-	if len(highRisk) > 20 {
-		highRisk = highRisk[:20]
-	}
-	if len(notFound) > 20 {
-		notFound = notFound[:20]
+	// Repositories whose owner account is gone can be re-registered.
+	for repo, info := range analysis.Repos {
+		if info.Severity == registry.SeverityCritical {
+			claimableRepos = append(claimableRepos, repo)
+		}
 	}
 
-	return SummaryData{
-		HighRiskCount:    len(highRisk),
-		MediumRiskCount:  len(mediumRisk),
-		NotFoundCount:    len(notFound),
-		HighRiskPackages: highRisk,
-		NotFoundPackages: notFound,
-	}
-}
-
-func generatePyPISummary(riskAnalysis map[string]registry.PyPIPackageInfo) SummaryData {
-	var highRisk, mediumRisk, notFound []string
-
-	for pkg, risk := range riskAnalysis {
-		if risk.RiskScore >= 70 {
-			highRisk = append(highRisk, pkg)
-		} else if risk.RiskScore >= 40 {
-			mediumRisk = append(mediumRisk, pkg)
-		} else if !risk.Exists {
+	for pkg, risk := range analysis.Packages {
+		switch risk.Status {
+		case registry.StatusNotFound:
 			notFound = append(notFound, pkg)
+		case registry.StatusUnknown:
+			unknown = append(unknown, pkg)
+		}
+		if risk.Severity == registry.SeverityConfusion {
+			confusion = append(confusion, pkg)
+		}
+		// Deprecated, abandoned or yanked: one step from being removed.
+		if registry.IsStale(risk) {
+			stale = append(stale, pkg)
 		}
-	}
-
-	sort.Strings(highRisk)
-	sort.Strings(mediumRisk)
-	sort.Strings(notFound)
-
-	if len(highRisk) > 20 {
-		highRisk = highRisk[:20]
-	}
-	if len(notFound) > 20 {
-		notFound = notFound[:20]
-	}
-
-	return SummaryData{
-		HighRiskCount:    len(highRisk),
-		MediumRiskCount:  len(mediumRisk),
-		NotFoundCount:    len(notFound),
-		HighRiskPackages: highRisk,
-		NotFoundPackages: notFound,
-	}
-}
-
-func generatePackagistSummary(riskAnalysis map[string]registry.PackagistPackageInfo) SummaryData {
-	var highRisk, mediumRisk, notFound []string
 
-	for pkg, risk := range riskAnalysis {
 		if risk.RiskScore >= 70 {
 			highRisk = append(highRisk, pkg)
 		} else if risk.RiskScore >= 40 {
 			mediumRisk = append(mediumRisk, pkg)
-		} else if !risk.Exists {
-			notFound = append(notFound, pkg)
 		}
 	}
 
+	sort.Strings(unclaimedNamespaces)
+	sort.Strings(confusion)
+	sort.Strings(claimableRepos)
+	sort.Strings(stale)
 	sort.Strings(highRisk)
 	sort.Strings(mediumRisk)
 	sort.Strings(notFound)
-
-	if len(highRisk) > 20 {
-		highRisk = highRisk[:20]
-	}
-	if len(notFound) > 20 {
-		notFound = notFound[:20]
-	}
+	sort.Strings(unknown)
 
 	return SummaryData{
-		HighRiskCount:    len(highRisk),
-		MediumRiskCount:  len(mediumRisk),
-		NotFoundCount:    len(notFound),
-		HighRiskPackages: highRisk,
-		NotFoundPackages: notFound,
-	}
-}
-
-func convertNPMAnalysis(analysis map[string]registry.NPMPackageInfo) map[string]interface{} {
-	result := make(map[string]interface{})
-	for pkg, risk := range analysis {
-		result[pkg] = risk
+		UnclaimedNamespaceCount: len(unclaimedNamespaces),
+		UnclaimedNamespaces:     unclaimedNamespaces,
+		ConfusionCount:          len(confusion),
+		ConfusionPackages:       truncate(confusion, 20),
+		ClaimableRepoCount:      len(claimableRepos),
+		ClaimableRepos:          claimableRepos,
+		StaleCount:              len(stale),
+		StalePackages:           truncate(stale, 20),
+		HighRiskCount:           len(highRisk),
+		MediumRiskCount:         len(mediumRisk),
+		NotFoundCount:           len(notFound),
+		UnknownCount:            len(unknown),
+		HighRiskPackages:        truncate(highRisk, 20),
+		NotFoundPackages:        truncate(notFound, 20),
+		UnknownPackages:         truncate(unknown, 20),
 	}
-	return result
 }
 
-func convertNPMDeps(deps map[string]map[string]string) map[string]interface{} {
-	result := make(map[string]interface{})
-	for file, dep := range deps {
-		result[file] = dep
+func truncate(list []string, n int) []string {
+	if len(list) > n {
+		return list[:n]
 	}
-	return result
-}
-
-func convertPyPIAnalysis(analysis map[string]registry.PyPIPackageInfo) map[string]interface{} {
-	result := make(map[string]interface{})
-	for pkg, risk := range analysis {
-		result[pkg] = risk
-	}
-	return result
-}
-
-func convertPackagistAnalysis(analysis map[string]registry.PackagistPackageInfo) map[string]interface{} {
-	result := make(map[string]interface{})
-	for pkg, risk := range analysis {
-		result[pkg] = risk
-	}
-	return result
-}
-
-func convertPythonDeps(deps map[string][]string) map[string]interface{} {
-	result := make(map[string]interface{})
-	for file, pkgs := range deps {
-		result[file] = pkgs
-	}
-	return result
-}
-
-func convertPHPDeps(deps map[string]map[string][]string) map[string]interface{} {
-	result := make(map[string]interface{})
-	for file, dep := range deps {
-		result[file] = dep
-	}
-	return result
+	return list
 }
 
 func printSummary(report ReportData) {
@@ -717,20 +704,83 @@ func printSummary(report ReportData) {
 
 	totalDeps := 0
 	totalNotFound := 0
+	totalUnknown := 0
+	totalNamespaces := 0
+	totalRepos := 0
+	totalConfusion := 0
+	totalStale := 0
 
 	for _, eco := range report.Ecosystems {
 		totalDeps += eco.TotalDependencies
+		totalNamespaces += eco.Summary.UnclaimedNamespaceCount
+		totalRepos += eco.Summary.ClaimableRepoCount
+		totalConfusion += eco.Summary.ConfusionCount
+		totalStale += eco.Summary.StaleCount
 		totalNotFound += eco.Summary.NotFoundCount
+		totalUnknown += eco.Summary.UnknownCount
 	}
 
 	fmt.Printf("📊 Dependencies: %d\n", totalDeps)
 	fmt.Printf("⚠️  Takeover targets: %d\n", totalNotFound)
+	if totalNamespaces > 0 {
+		fmt.Printf("🔥 Unclaimed namespaces: %d\n", totalNamespaces)
+	}
+	if totalConfusion > 0 {
+		fmt.Printf("🔥 Dependency confusion (internal names unclaimed publicly): %d\n", totalConfusion)
+	}
+	if totalRepos > 0 {
+		fmt.Printf("🔥 Repo-jackable git dependencies: %d\n", totalRepos)
+	}
+	if totalStale > 0 {
+		fmt.Printf("⏳ Deprecated, abandoned or yanked: %d\n", totalStale)
+	}
+	if totalUnknown > 0 {
+		fmt.Printf("❓ Unknown (lookup failed): %d\n", totalUnknown)
+	}
 
 	for ecoName, ecoData := range report.Ecosystems {
+		if ecoData.Summary.UnclaimedNamespaceCount > 0 {
+			fmt.Printf("\n🔥 [%s] %d UNCLAIMED NAMESPACES (every package under them is claimable):\n", strings.ToUpper(ecoName), ecoData.Summary.UnclaimedNamespaceCount)
+			for _, ns := range ecoData.Summary.UnclaimedNamespaces {
+				fmt.Printf("  • %s\n", ns)
+			}
+		}
+		if ecoData.Summary.ConfusionCount > 0 {
+			fmt.Printf("\n🔥 [%s] %d INTERNAL PACKAGES UNCLAIMED ON THE PUBLIC REGISTRY (dependency confusion):\n", strings.ToUpper(ecoName), ecoData.Summary.ConfusionCount)
+			for _, pkg := range ecoData.Summary.ConfusionPackages {
+				fmt.Printf("  • %s\n", pkg)
+			}
+		}
+		if ecoData.Summary.ClaimableRepoCount > 0 {
+			fmt.Printf("\n🔥 [%s] %d GIT DEPENDENCIES ON UNCLAIMED GITHUB OWNERS (repo-jacking):\n", strings.ToUpper(ecoName), ecoData.Summary.ClaimableRepoCount)
+			for _, repo := range ecoData.Summary.ClaimableRepos {
+				fmt.Printf("  • github.com/%s\n", repo)
+			}
+		}
 		if ecoData.Summary.NotFoundCount > 0 {
 			fmt.Printf("\n🚨 [%s] %d NOT FOUND:\n", strings.ToUpper(ecoName), ecoData.Summary.NotFoundCount)
 			for _, pkg := range ecoData.Summary.NotFoundPackages {
-				fmt.Printf("  • %s\n", pkg)
+				if path, ok := ecoData.RiskAnalysis[pkg].Metadata["dependency_path"].([]string); ok {
+					fmt.Printf("  • %s (via %s)\n", pkg, strings.Join(path, " → "))
+				} else {
+					fmt.Printf("  • %s\n", pkg)
+				}
+			}
+		}
+		if ecoData.Summary.StaleCount > 0 {
+			fmt.Printf("\n⏳ [%s] %d DEPRECATED, ABANDONED OR YANKED (one step from removal):\n", strings.ToUpper(ecoName), ecoData.Summary.StaleCount)
+			for _, pkg := range ecoData.Summary.StalePackages {
+				if replacement, ok := ecoData.RiskAnalysis[pkg].Metadata["replacement"]; ok {
+					fmt.Printf("  • %s (replaced by %v)\n", pkg, replacement)
+				} else {
+					fmt.Printf("  • %s\n", pkg)
+				}
+			}
+		}
+		if ecoData.Summary.UnknownCount > 0 {
+			fmt.Printf("\n❓ [%s] %d UNKNOWN (lookup failed, re-run with --retry-unknown):\n", strings.ToUpper(ecoName), ecoData.Summary.UnknownCount)
+			for _, pkg := range ecoData.Summary.UnknownPackages {
+				fmt.Printf("  • %s (%s)\n", pkg, ecoData.RiskAnalysis[pkg].Error)
 			}
 		}
 	}
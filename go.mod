@@ -3,8 +3,10 @@ module github.com/Swayamyadav01/Deptakeover
 go 1.21
 
 require (
+	github.com/BurntSushi/toml v1.3.2
 	github.com/go-git/go-git/v5 v5.11.0
 	github.com/spf13/cobra v1.8.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
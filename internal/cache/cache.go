@@ -0,0 +1,264 @@
+// Package cache stores registry answers on disk so that repeated scans (and
+// org scans hitting the same popular packages in every repo) do not have to
+// query the registries again.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultPositiveTTL is how long "package exists" answers are kept.
+	DefaultPositiveTTL = 24 * time.Hour
+	// DefaultNegativeTTL is how long "package not found" answers are kept.
+	// It is shorter because a missing name can be claimed at any moment.
+	DefaultNegativeTTL = time.Hour
+)
+
+// Cache is a directory of JSON entries keyed by ecosystem and package name.
+type Cache struct {
+	dir         string
+	positiveTTL time.Duration
+	negativeTTL time.Duration
+	refresh     bool
+}
+
+// Options configures a Cache.
+type Options struct {
+	PositiveTTL time.Duration
+	NegativeTTL time.Duration
+	// Refresh ignores existing entries on read but still stores new answers.
+	Refresh bool
+}
+
+// schemaVersion is stored with every entry and must be bumped whenever the
+// shape of cached values changes, such as registry.PackageInfo gaining a
+// field the scanner relies on. Entries from another schema are treated as
+// expired.
+const schemaVersion = 2
+
+type entry struct {
+	Schema    int             `json:"schema"`
+	Ecosystem string          `json:"ecosystem"`
+	Name      string          `json:"name"`
+	Negative  bool            `json:"negative"`
+	StoredAt  time.Time       `json:"stored_at"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// Stats summarizes the contents of a cache directory.
+type Stats struct {
+	Dir      string
+	Entries  int
+	Positive int
+	Negative int
+	Expired  int
+	Bytes    int64
+}
+
+// DefaultDir returns the per-user cache directory for deptakeover.
+func DefaultDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "deptakeover", "registry"), nil
+}
+
+// Open creates dir if needed and returns a cache rooted there.
+func Open(dir string, opts Options) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating cache dir: %w", err)
+	}
+	if opts.PositiveTTL <= 0 {
+		opts.PositiveTTL = DefaultPositiveTTL
+	}
+	if opts.NegativeTTL <= 0 {
+		opts.NegativeTTL = DefaultNegativeTTL
+	}
+	return &Cache{
+		dir:         dir,
+		positiveTTL: opts.PositiveTTL,
+		negativeTTL: opts.NegativeTTL,
+		refresh:     opts.Refresh,
+	}, nil
+}
+
+// Get decodes the cached value for ecosystem/name into v. It reports false
+// if there is no entry, the entry has expired or the cache is refreshing.
+func (c *Cache) Get(ecosystem, name string, v interface{}) bool {
+	if c.refresh {
+		return false
+	}
+
+	e, err := c.read(c.path(ecosystem, name))
+	if err != nil || e.Ecosystem != ecosystem || e.Name != name || c.expired(e) {
+		return false
+	}
+	return json.Unmarshal(e.Value, v) == nil
+}
+
+// Put stores v for ecosystem/name. Negative marks a "not found" answer,
+// which expires after the negative TTL.
+func (c *Cache) Put(ecosystem, name string, negative bool, v interface{}) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry{
+		Schema:    schemaVersion,
+		Ecosystem: ecosystem,
+		Name:      name,
+		Negative:  negative,
+		StoredAt:  time.Now(),
+		Value:     value,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Write to a temp file and rename so concurrent readers never see a
+	// partially written entry.
+	path := c.path(ecosystem, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Stats walks the cache directory and counts its entries.
+func (c *Cache) Stats() (Stats, error) {
+	stats := Stats{Dir: c.dir}
+
+	err := filepath.Walk(c.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(info.Name(), ".json") {
+			return nil
+		}
+
+		e, err := c.read(path)
+		if err != nil {
+			return nil
+		}
+
+		stats.Entries++
+		stats.Bytes += info.Size()
+		if e.Negative {
+			stats.Negative++
+		} else {
+			stats.Positive++
+		}
+		if c.expired(e) {
+			stats.Expired++
+		}
+		return nil
+	})
+
+	return stats, err
+}
+
+// Clear removes every entry from the cache. Only the files Put writes are
+// deleted, along with the directories they leave empty, so pointing the
+// cache at a directory that holds anything else does not lose it.
+func (c *Cache) Clear() error {
+	ecosystems, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+	for _, eco := range ecosystems {
+		if !eco.IsDir() {
+			continue
+		}
+		ecoDir := filepath.Join(c.dir, eco.Name())
+		shards, err := os.ReadDir(ecoDir)
+		if err != nil {
+			return err
+		}
+		for _, shard := range shards {
+			if !shard.IsDir() || !isHex(shard.Name(), 2) {
+				continue
+			}
+			shardDir := filepath.Join(ecoDir, shard.Name())
+			files, err := os.ReadDir(shardDir)
+			if err != nil {
+				return err
+			}
+			for _, f := range files {
+				if f.IsDir() || !isEntryFile(shard.Name(), f.Name()) {
+					continue
+				}
+				if err := os.Remove(filepath.Join(shardDir, f.Name())); err != nil {
+					return err
+				}
+			}
+			// Fails, and is left alone, unless the shard is now empty.
+			os.Remove(shardDir)
+		}
+		os.Remove(ecoDir)
+	}
+	return nil
+}
+
+// isEntryFile reports whether name is an entry, or the temp file of one being
+// written, inside the shard directory Put stores it in.
+func isEntryFile(shard, name string) bool {
+	if strings.HasPrefix(name, ".tmp-") {
+		return true
+	}
+	key, ok := strings.CutSuffix(name, ".json")
+	return ok && isHex(key, sha256.Size*2) && key[:2] == shard
+}
+
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+func (c *Cache) path(ecosystem, name string) string {
+	sum := sha256.Sum256([]byte(ecosystem + "\x00" + name))
+	key := hex.EncodeToString(sum[:])
+	return filepath.Join(c.dir, ecosystem, key[:2], key+".json")
+}
+
+func (c *Cache) read(path string) (entry, error) {
+	var e entry
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return e, err
+	}
+	err = json.Unmarshal(data, &e)
+	return e, err
+}
+
+func (c *Cache) expired(e entry) bool {
+	if e.Schema != schemaVersion {
+		return true
+	}
+	ttl := c.positiveTTL
+	if e.Negative {
+		ttl = c.negativeTTL
+	}
+	return time.Since(e.StoredAt) > ttl
+}
@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClearKeepsForeignFiles(t *testing.T) {
+	dir := t.TempDir()
+	c, err := Open(dir, Options{})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if err := c.Put("npm", "left-pad", false, map[string]string{"name": "left-pad"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := c.Put("pypi", "requests", true, nil); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	// Files the cache did not write, including ones inside an ecosystem
+	// directory, must survive a Clear.
+	foreign := []string{
+		"notes.txt",
+		filepath.Join("src", "main.go"),
+		filepath.Join("npm", "README.md"),
+		filepath.Join("npm", "ab", "config.json"),
+	}
+	for _, name := range foreign {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("keep"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := c.Clear(); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+
+	var v map[string]string
+	if c.Get("npm", "left-pad", &v) {
+		t.Error("npm entry survived Clear")
+	}
+	stats, err := c.Stats()
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.Entries != 0 {
+		t.Errorf("Stats().Entries = %d after Clear, want 0", stats.Entries)
+	}
+	for _, name := range foreign {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s was removed: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pypi")); !os.IsNotExist(err) {
+		t.Errorf("empty pypi directory was kept: %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package ecosystem
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/Swayamyadav01/Deptakeover/internal/cache"
+	"github.com/Swayamyadav01/Deptakeover/internal/registry"
+)
+
+var lookupCache *cache.Cache
+
+// SetCache makes Analyze consult c before querying a registry and store
+// definite answers in it. A nil cache disables caching.
+func SetCache(c *cache.Cache) {
+	lookupCache = c
+}
+
+// cachedCheck wraps check with the lookup cache, storing answers under kind
+// (the ecosystem name, or a derived name for other kinds of lookups). Unknown
+// results are never stored so that a flaky network does not poison later
+// scans.
+func cachedCheck(kind string, check func(string) registry.PackageInfo, hits *int64) func(string) registry.PackageInfo {
+	if lookupCache == nil {
+		return check
+	}
+
+	return func(name string) registry.PackageInfo {
+		var info registry.PackageInfo
+		if lookupCache.Get(kind, name, &info) {
+			atomic.AddInt64(hits, 1)
+			return info
+		}
+
+		info = check(name)
+		if info.Status != registry.StatusUnknown {
+			if err := lookupCache.Put(kind, name, info.Status == registry.StatusNotFound, info); err != nil {
+				fmt.Printf("Warning: could not cache %s: %v\n", name, err)
+			}
+		}
+		return info
+	}
+}
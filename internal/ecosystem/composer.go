@@ -0,0 +1,55 @@
+package ecosystem
+
+import (
+	"strings"
+
+	"github.com/Swayamyadav01/Deptakeover/internal/registry"
+	"github.com/Swayamyadav01/Deptakeover/internal/scanner"
+	"github.com/Swayamyadav01/Deptakeover/internal/version"
+)
+
+type composerEcosystem struct{}
+
+func init() {
+	Register(composerEcosystem{}, "php")
+}
+
+func (composerEcosystem) Name() string { return "composer" }
+
+func (composerEcosystem) FindManifests(repoPath string) []string {
+	return scanner.FindComposerManifests(repoPath)
+}
+
+func (composerEcosystem) ExtractDependencies(manifestPath string) ([]scanner.Dependency, error) {
+	return scanner.ExtractPHPManifest(manifestPath)
+}
+
+func (composerEcosystem) WorkspacePackages(repoPath string) map[string]string {
+	return scanner.FindComposerPathPackages(repoPath)
+}
+
+func (composerEcosystem) PrivateRegistries(repoPath string) []scanner.PrivateRegistry {
+	return scanner.FindComposerPrivateRegistries(repoPath)
+}
+
+func (composerEcosystem) NormalizeName(name string) string { return strings.ToLower(name) }
+
+func (composerEcosystem) CheckPackage(name string) registry.PackageInfo {
+	return registry.CheckPackagistPackageRisk(name)
+}
+
+func (composerEcosystem) Namespace(name string) (string, bool) {
+	return registry.PackagistVendor(name)
+}
+
+func (composerEcosystem) CheckNamespace(vendor string) registry.PackageInfo {
+	return registry.CheckPackagistVendor(vendor)
+}
+
+// ReservesNamespaces is true: Packagist only lets a vendor's maintainers
+// submit packages under it.
+func (composerEcosystem) ReservesNamespaces() bool { return true }
+
+func (composerEcosystem) Satisfiable(constraint string, published []string) (bool, bool) {
+	return version.ComposerSatisfiable(constraint, published)
+}
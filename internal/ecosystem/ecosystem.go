@@ -0,0 +1,512 @@
+// Package ecosystem ties manifest scanning and registry lookups together
+// behind a single interface, one implementation per package ecosystem.
+package ecosystem
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Swayamyadav01/Deptakeover/internal/github"
+	"github.com/Swayamyadav01/Deptakeover/internal/registry"
+	"github.com/Swayamyadav01/Deptakeover/internal/scanner"
+)
+
+// Ecosystem describes how to find, parse and check the dependencies of a
+// single package ecosystem such as npm or PyPI.
+type Ecosystem interface {
+	// Name is the identifier used on the command line and in reports.
+	Name() string
+	// FindManifests returns every manifest file under repoPath.
+	FindManifests(repoPath string) []string
+	// ExtractDependencies parses a single manifest file.
+	ExtractDependencies(manifestPath string) ([]scanner.Dependency, error)
+	// NormalizeName returns the canonical registry name for a dependency.
+	NormalizeName(name string) string
+	// CheckPackage looks a normalized package name up on the public registry.
+	CheckPackage(name string) registry.PackageInfo
+}
+
+// NamespaceChecker is implemented by ecosystems whose package names live
+// under namespaces that can be claimed on their own, such as npm scopes.
+// An unclaimed namespace is worse than a missing package: every name under
+// it is up for grabs.
+type NamespaceChecker interface {
+	// Namespace returns the namespace of a normalized package name, if any.
+	Namespace(name string) (string, bool)
+	// CheckNamespace looks up whether a namespace is claimed on the registry.
+	CheckNamespace(namespace string) registry.PackageInfo
+}
+
+// NamespaceReserver is implemented by NamespaceCheckers whose registry only
+// lets the owners of a claimed namespace publish under it, such as npm scopes
+// and Packagist vendors. A missing package under a claimed namespace is then not
+// claimable by outsiders.
+type NamespaceReserver interface {
+	NamespaceChecker
+	// ReservesNamespaces reports whether claimed namespaces are reserved
+	// for their owners.
+	ReservesNamespaces() bool
+}
+
+// WorkspaceResolver is implemented by ecosystems with monorepo workspaces,
+// whose member packages are resolved locally instead of from the registry.
+type WorkspaceResolver interface {
+	// WorkspacePackages returns the workspace members under repoPath, keyed
+	// by package name, with the directory each lives in.
+	WorkspacePackages(repoPath string) map[string]string
+}
+
+// PrivateRegistryFinder is implemented by ecosystems whose projects can
+// configure private registries. Packages meant to come from one of them but
+// unclaimed on the public registry are dependency-confusion targets.
+type PrivateRegistryFinder interface {
+	// PrivateRegistries returns the private registries configured under
+	// repoPath.
+	PrivateRegistries(repoPath string) []scanner.PrivateRegistry
+}
+
+// ConstraintChecker is implemented by ecosystems that can evaluate declared
+// version constraints. A package whose declared versions exist nowhere on the
+// public registry was likely meant to come from a private one.
+type ConstraintChecker interface {
+	// Satisfiable reports whether any of the published versions satisfies
+	// constraint; ok is false when the constraint cannot be evaluated.
+	Satisfiable(constraint string, published []string) (satisfied, ok bool)
+}
+
+var (
+	ecosystems = make(map[string]Ecosystem)
+	aliases    = make(map[string]string)
+)
+
+// Register makes an ecosystem available under its name and any aliases.
+// It panics if the name or an alias is already taken.
+func Register(eco Ecosystem, alias ...string) {
+	name := eco.Name()
+	if _, exists := aliases[name]; exists {
+		panic("ecosystem: duplicate registration of " + name)
+	}
+	ecosystems[name] = eco
+	aliases[name] = name
+
+	for _, a := range alias {
+		if _, exists := aliases[a]; exists {
+			panic("ecosystem: duplicate alias " + a)
+		}
+		aliases[a] = name
+	}
+}
+
+// Get returns the ecosystem registered under name or one of its aliases.
+func Get(name string) (Ecosystem, bool) {
+	canonical, exists := aliases[name]
+	if !exists {
+		return nil, false
+	}
+	return ecosystems[canonical], true
+}
+
+// Names returns the canonical names of all registered ecosystems, sorted.
+func Names() []string {
+	var names []string
+	for name := range ecosystems {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Aliases returns every accepted name, canonical names included, sorted.
+func Aliases() []string {
+	var names []string
+	for a := range aliases {
+		names = append(names, a)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ScanResult holds the dependencies found in a repository for one ecosystem.
+type ScanResult struct {
+	DependenciesByFile map[string][]scanner.Dependency
+	Packages           []string
+	// Types lists the dependency types (dependencies, peerDependencies,
+	// require-dev...) each package is declared with, sorted.
+	Types map[string][]string
+	// Internal maps the dependencies that are workspace members to their
+	// directory, empty when only a workspace: reference names them.
+	Internal map[string]string
+	// PrivateRegistries lists the private registries the repository's
+	// configuration files point at.
+	PrivateRegistries []scanner.PrivateRegistry
+	// PrivateSources maps packages that a manifest or lockfile pins to a
+	// private registry to that registry.
+	PrivateSources map[string]scanner.PrivateRegistry
+	// Repos lists the GitHub repositories ("owner/repo") that git-hosted
+	// dependencies are fetched from, sorted.
+	Repos []string
+	// Constraints lists the version constraints (or locked versions) each
+	// registry package is declared with.
+	Constraints map[string][]string
+}
+
+// weakDependencyTypes are dependency types that are not installed
+// unconditionally, so a missing package declared only this way is less
+// likely to be pulled in.
+var weakDependencyTypes = map[string]bool{
+	"peerDependencies":     true,
+	"optionalDependencies": true,
+	// pip constraint files only pin versions of packages pulled in
+	// by something else.
+	"constraints": true,
+	// Composer suggestions are only installed if the user chooses to.
+	"suggest": true,
+}
+
+// Scan extracts dependencies from every manifest in repoPath and collects
+// the unique, normalized package names to check. Dependencies keep the
+// spelling used in their manifest; findings are merged by normalized name.
+// Aliases are checked under the package they install, and dependencies not
+// installed from the registry (git, tarball, local paths) are not checked.
+// Workspace members are checked too: they are internal names that must not
+// be left unclaimed on the public registry.
+func Scan(eco Ecosystem, repoPath string) ScanResult {
+	result := ScanResult{
+		DependenciesByFile: make(map[string][]scanner.Dependency),
+		Types:              make(map[string][]string),
+		Internal:           make(map[string]string),
+		PrivateSources:     make(map[string]scanner.PrivateRegistry),
+		Constraints:        make(map[string][]string),
+	}
+
+	var workspace map[string]string
+	if wr, ok := eco.(WorkspaceResolver); ok {
+		workspace = wr.WorkspacePackages(repoPath)
+	}
+	if pf, ok := eco.(PrivateRegistryFinder); ok {
+		result.PrivateRegistries = pf.PrivateRegistries(repoPath)
+	}
+
+	seen := make(map[string]bool)
+	typeSeen := make(map[string]bool)
+	repoSeen := make(map[string]bool)
+	constraintSeen := make(map[string]bool)
+	for _, manifest := range eco.FindManifests(repoPath) {
+		deps, err := eco.ExtractDependencies(manifest)
+		if err != nil || len(deps) == 0 {
+			continue
+		}
+		result.DependenciesByFile[manifest] = deps
+
+		for i, dep := range deps {
+			if dep.Kind == scanner.SpecGit {
+				if repo, ok := github.ParseRepoURL(dep.Resolved); ok && !repoSeen[strings.ToLower(repo)] {
+					repoSeen[strings.ToLower(repo)] = true
+					result.Repos = append(result.Repos, repo)
+				}
+			}
+			name := dep.Name
+			if dep.Canonical != "" {
+				name = dep.Canonical
+			}
+			if dir, ok := workspace[name]; ok && (dep.FromRegistry() || dep.Kind == scanner.SpecWorkspace) {
+				deps[i].Kind = scanner.SpecWorkspace
+				dep.Kind = scanner.SpecWorkspace
+				result.Internal[eco.NormalizeName(name)] = dir
+			} else if dep.Kind == scanner.SpecWorkspace {
+				result.Internal[eco.NormalizeName(name)] = ""
+			} else if !dep.FromRegistry() {
+				continue
+			}
+
+			name = eco.NormalizeName(name)
+			if name != dep.Name {
+				deps[i].Canonical = name
+			}
+			if !seen[name] {
+				seen[name] = true
+				result.Packages = append(result.Packages, name)
+			}
+			if dep.Source != "" && scanner.IsPrivateRegistry(dep.Source) {
+				result.PrivateSources[name] = scanner.PrivateRegistry{File: manifest, URL: dep.Source}
+			}
+			// An alias's version is its target spec, and workspace
+			// members are resolved locally.
+			if dep.Version != "" && dep.Kind != scanner.SpecAlias && dep.Kind != scanner.SpecWorkspace && !constraintSeen[name+"\x00"+dep.Version] {
+				constraintSeen[name+"\x00"+dep.Version] = true
+				result.Constraints[name] = append(result.Constraints[name], dep.Version)
+			}
+			if dep.Type != "" && !typeSeen[name+"\x00"+dep.Type] {
+				typeSeen[name+"\x00"+dep.Type] = true
+				result.Types[name] = append(result.Types[name], dep.Type)
+			}
+		}
+	}
+
+	sort.Strings(result.Packages)
+	sort.Strings(result.Repos)
+	for _, types := range result.Types {
+		sort.Strings(types)
+	}
+	return result
+}
+
+// Analysis holds the registry results for one ecosystem.
+type Analysis struct {
+	Packages map[string]registry.PackageInfo
+	// Namespaces holds the namespace lookups made for missing packages, for
+	// ecosystems implementing NamespaceChecker.
+	Namespaces map[string]registry.PackageInfo
+	// Repos holds the GitHub lookups for git-hosted dependencies.
+	Repos map[string]registry.PackageInfo
+}
+
+// Analyze checks every scanned package against the ecosystem's registry,
+// using the lookup cache when one is set, and their dependencies down to the
+// depth set with SetDepth.
+func Analyze(eco Ecosystem, scan ScanResult) Analysis {
+	packages := scan.Packages
+
+	var hits int64
+	check := cachedCheck(eco.Name(), eco.CheckPackage, &hits)
+	analysis := Analysis{
+		Packages: registry.AnalyzeDependencyRisks(packages, check),
+	}
+	lookups := len(packages)
+	if transitiveDepth > 0 {
+		lookups += resolveTransitive(eco, &analysis, scan, check)
+	}
+
+	weighDependencyTypes(&analysis, scan)
+	classifyInternal(&analysis, scan)
+	classifyPrivate(eco, &analysis, scan)
+	if cc, ok := eco.(ConstraintChecker); ok {
+		checkConstraints(cc, &analysis, scan)
+	}
+	for name, info := range analysis.Packages {
+		info.Versions, info.Dependencies = nil, nil
+		analysis.Packages[name] = info
+	}
+
+	if nc, ok := eco.(NamespaceChecker); ok {
+		lookups += analyzeNamespaces(eco, nc, &analysis, &hits)
+	}
+
+	if len(scan.Repos) > 0 {
+		fmt.Printf("Checking %d GitHub repositories...\n", len(scan.Repos))
+		analysis.Repos = make(map[string]registry.PackageInfo)
+		for i, info := range registry.LookupAll(scan.Repos, cachedCheck("github", github.CheckRepo, &hits)) {
+			analysis.Repos[scan.Repos[i]] = info
+		}
+		lookups += len(scan.Repos)
+	}
+
+	if lookupCache != nil {
+		fmt.Printf("Cache: %d/%d lookups served from cache\n", hits, lookups)
+	}
+	return analysis
+}
+
+// weighDependencyTypes lowers the severity of missing packages that are only
+// declared as peer, optional or constraint dependencies: they are not
+// installed by default, so claiming them is less likely to reach users.
+func weighDependencyTypes(analysis *Analysis, scan ScanResult) {
+	for name, info := range analysis.Packages {
+		types := scan.Types[name]
+		if info.Status != registry.StatusNotFound || info.Severity != registry.SeverityHigh || len(types) == 0 {
+			continue
+		}
+
+		weak := true
+		for _, t := range types {
+			weak = weak && weakDependencyTypes[t]
+		}
+		if !weak {
+			continue
+		}
+
+		info.RiskScore = 60
+		info.Severity = registry.SeverityMedium
+		info.Signals = append(info.Signals, "optional_or_peer_only")
+		analysis.Packages[name] = info
+	}
+}
+
+// classifyInternal marks workspace members. One that is unclaimed on the
+// public registry is a dependency-confusion risk: an attacker publishing the
+// name can get it installed wherever the workspace is not used, such as a
+// standalone install or a consumer outside the monorepo.
+func classifyInternal(analysis *Analysis, scan ScanResult) {
+	for name, dir := range scan.Internal {
+		info, ok := analysis.Packages[name]
+		if !ok {
+			continue
+		}
+
+		info.Signals = append(info.Signals, "internal_package")
+		if info.Metadata == nil {
+			info.Metadata = make(map[string]interface{})
+		}
+		if dir != "" {
+			info.Metadata["workspace"] = dir
+		}
+		if info.Status == registry.StatusNotFound {
+			info.RiskScore = 100
+			info.Severity = registry.SeverityConfusion
+			info.Signals = append(info.Signals, "internal_unclaimed_on_public_registry")
+		}
+		analysis.Packages[name] = info
+	}
+}
+
+// classifyPrivate marks missing packages that the repository expects from a
+// private registry: one their manifest pins them to, a registry configured
+// for their scope, or a private default or extra index serving every name.
+// Whoever publishes the name on the public registry can get it installed
+// instead (dependency confusion).
+func classifyPrivate(eco Ecosystem, analysis *Analysis, scan ScanResult) {
+	nc, _ := eco.(NamespaceChecker)
+	for name, info := range analysis.Packages {
+		if info.Status != registry.StatusNotFound {
+			continue
+		}
+
+		var scope string
+		if nc != nil {
+			scope, _ = nc.Namespace(name)
+		}
+
+		match, pinned := scan.PrivateSources[name]
+		found := pinned
+		for _, reg := range scan.PrivateRegistries {
+			if pinned {
+				break
+			}
+			if reg.Scope != "" && reg.Scope == scope {
+				match, found = reg, true
+				break
+			}
+			if reg.Scope == "" && !found {
+				match, found = reg, true
+			}
+		}
+		if !found {
+			continue
+		}
+
+		if info.Metadata == nil {
+			info.Metadata = make(map[string]interface{})
+		}
+		info.RiskScore = 100
+		info.Severity = registry.SeverityConfusion
+		info.Signals = append(info.Signals, "private_registry_unclaimed_on_public_registry")
+		info.Metadata["private_registry"] = match.URL
+		info.Metadata["private_registry_config"] = match.File
+		analysis.Packages[name] = info
+	}
+}
+
+// checkConstraints flags published packages whose declared constraints no
+// public version satisfies, like "lodash==99.0.0". The pinned version likely
+// comes from a private registry; whoever can publish that version publicly
+// can get it installed instead (dependency confusion).
+func checkConstraints(cc ConstraintChecker, analysis *Analysis, scan ScanResult) {
+	for name, info := range analysis.Packages {
+		if !info.Exists || len(info.Versions) == 0 {
+			continue
+		}
+
+		var unsatisfied []string
+		for _, constraint := range scan.Constraints[name] {
+			if satisfied, ok := cc.Satisfiable(constraint, info.Versions); ok && !satisfied {
+				unsatisfied = append(unsatisfied, constraint)
+			}
+		}
+		if len(unsatisfied) == 0 {
+			continue
+		}
+
+		fmt.Printf("Info: No public version of %s satisfies %s\n", name, strings.Join(unsatisfied, ", "))
+		if info.Metadata == nil {
+			info.Metadata = make(map[string]interface{})
+		}
+		info.RiskScore = max(info.RiskScore, 80)
+		if info.Severity != registry.SeverityCritical {
+			info.Severity = registry.SeverityConfusion
+		}
+		info.Signals = append(info.Signals, "no_public_version_satisfies_constraint")
+		info.Metadata["unsatisfied_constraints"] = unsatisfied
+		analysis.Packages[name] = info
+	}
+}
+
+// analyzeNamespaces checks the namespaces of missing packages and escalates
+// those packages when their namespace is unclaimed. For ecosystems
+// implementing NamespaceReserver, missing packages under a claimed namespace
+// are marked reserved instead, unless they are dependency-confusion findings:
+// whoever owns the namespace may not be who the repository expects. It
+// returns the number of namespaces looked up.
+func analyzeNamespaces(eco Ecosystem, nc NamespaceChecker, analysis *Analysis, hits *int64) int {
+	// A package that exists proves its namespace is claimed, so only the
+	// namespaces of missing packages need a lookup.
+	members := make(map[string][]string)
+	var namespaces []string
+	for name, info := range analysis.Packages {
+		if info.Status != registry.StatusNotFound {
+			continue
+		}
+		if ns, ok := nc.Namespace(name); ok {
+			if _, seen := members[ns]; !seen {
+				namespaces = append(namespaces, ns)
+			}
+			members[ns] = append(members[ns], name)
+		}
+	}
+	if len(namespaces) == 0 {
+		return 0
+	}
+	sort.Strings(namespaces)
+
+	fmt.Printf("Checking %d namespaces...\n", len(namespaces))
+	check := cachedCheck(eco.Name()+"-namespace", nc.CheckNamespace, hits)
+	analysis.Namespaces = make(map[string]registry.PackageInfo)
+	nr, reserves := nc.(NamespaceReserver)
+	reserves = reserves && nr.ReservesNamespaces()
+	for i, info := range registry.LookupAll(namespaces, check) {
+		ns := namespaces[i]
+		analysis.Namespaces[ns] = info
+		switch {
+		case info.Status == registry.StatusNotFound:
+			for _, name := range members[ns] {
+				pkg := analysis.Packages[name]
+				pkg.Severity = registry.SeverityCritical
+				pkg.Signals = append(pkg.Signals, "namespace_unclaimed")
+				analysis.Packages[name] = pkg
+			}
+		case info.Status == registry.StatusExists && reserves:
+			for _, name := range members[ns] {
+				pkg := analysis.Packages[name]
+				if pkg.Severity == registry.SeverityConfusion {
+					continue
+				}
+				if pkg.Metadata == nil {
+					pkg.Metadata = make(map[string]interface{})
+				}
+				pkg.Status = registry.StatusReserved
+				pkg.RiskScore = 40
+				pkg.Severity = registry.SeverityMedium
+				pkg.Signals = append(pkg.Signals, "namespace_claimed")
+				if count, ok := info.Metadata["package_count"]; ok {
+					pkg.Metadata["namespace_packages"] = count
+				}
+				if owner, ok := info.Metadata["owner_type"]; ok {
+					pkg.Metadata["namespace_owner_type"] = owner
+				}
+				analysis.Packages[name] = pkg
+			}
+		}
+	}
+
+	return len(namespaces)
+}
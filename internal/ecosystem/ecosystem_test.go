@@ -0,0 +1,62 @@
+package ecosystem
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Swayamyadav01/Deptakeover/internal/registry"
+)
+
+// fakeNPM answers scope lookups from a table instead of the registry.
+type fakeNPM struct {
+	npmEcosystem
+	scopes map[string]registry.PackageInfo
+}
+
+func (f fakeNPM) CheckNamespace(scope string) registry.PackageInfo {
+	return f.scopes[scope]
+}
+
+func TestAnalyzeNamespacesNPM(t *testing.T) {
+	eco := fakeNPM{scopes: map[string]registry.PackageInfo{
+		"@acme":  {Status: registry.StatusExists, Exists: true, Metadata: map[string]interface{}{"owner_type": "org"}},
+		"@ghost": {Status: registry.StatusNotFound},
+	}}
+	missing := func(severity registry.Severity) registry.PackageInfo {
+		return registry.PackageInfo{Status: registry.StatusNotFound, Severity: severity, RiskScore: 90, Signals: []string{"package_not_found"}}
+	}
+	analysis := Analysis{Packages: map[string]registry.PackageInfo{
+		"@acme/missing":  missing(registry.SeverityHigh),
+		"@acme/internal": missing(registry.SeverityConfusion),
+		"@ghost/missing": missing(registry.SeverityHigh),
+		"left-pad":       {Status: registry.StatusExists, Exists: true},
+	}}
+
+	if got := analyzeNamespaces(eco, eco, &analysis, new(int64)); got != 2 {
+		t.Errorf("analyzeNamespaces looked up %d namespaces, want 2", got)
+	}
+
+	want := map[string]registry.PackageInfo{
+		// Only @acme's owner can publish under @acme.
+		"@acme/missing": {
+			Status:    registry.StatusReserved,
+			Severity:  registry.SeverityMedium,
+			RiskScore: 40,
+			Signals:   []string{"package_not_found", "namespace_claimed"},
+			Metadata:  map[string]interface{}{"namespace_owner_type": "org"},
+		},
+		// A confusion finding stays one: the scope's owner may not be who
+		// the repository expects.
+		"@acme/internal": missing(registry.SeverityConfusion),
+		"@ghost/missing": {
+			Status:    registry.StatusNotFound,
+			Severity:  registry.SeverityCritical,
+			RiskScore: 90,
+			Signals:   []string{"package_not_found", "namespace_unclaimed"},
+		},
+		"left-pad": {Status: registry.StatusExists, Exists: true},
+	}
+	if !reflect.DeepEqual(analysis.Packages, want) {
+		t.Errorf("got  %+v\nwant %+v", analysis.Packages, want)
+	}
+}
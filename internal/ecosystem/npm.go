@@ -0,0 +1,53 @@
+package ecosystem
+
+import (
+	"github.com/Swayamyadav01/Deptakeover/internal/registry"
+	"github.com/Swayamyadav01/Deptakeover/internal/scanner"
+	"github.com/Swayamyadav01/Deptakeover/internal/version"
+)
+
+type npmEcosystem struct{}
+
+func init() {
+	Register(npmEcosystem{})
+}
+
+func (npmEcosystem) Name() string { return "npm" }
+
+func (npmEcosystem) FindManifests(repoPath string) []string {
+	return scanner.FindNPMManifests(repoPath)
+}
+
+func (npmEcosystem) ExtractDependencies(manifestPath string) ([]scanner.Dependency, error) {
+	return scanner.ExtractNPMManifest(manifestPath)
+}
+
+func (npmEcosystem) WorkspacePackages(repoPath string) map[string]string {
+	return scanner.FindNPMWorkspacePackages(repoPath)
+}
+
+func (npmEcosystem) PrivateRegistries(repoPath string) []scanner.PrivateRegistry {
+	return scanner.FindNPMPrivateRegistries(repoPath)
+}
+
+func (npmEcosystem) NormalizeName(name string) string { return name }
+
+func (npmEcosystem) CheckPackage(name string) registry.PackageInfo {
+	return registry.CheckNPMPackageRisk(name)
+}
+
+func (npmEcosystem) Namespace(name string) (string, bool) {
+	return registry.NPMScope(name)
+}
+
+func (npmEcosystem) CheckNamespace(scope string) registry.PackageInfo {
+	return registry.CheckNPMScope(scope)
+}
+
+// ReservesNamespaces is true: only the user or org owning a scope can
+// publish under it.
+func (npmEcosystem) ReservesNamespaces() bool { return true }
+
+func (npmEcosystem) Satisfiable(constraint string, published []string) (bool, bool) {
+	return version.NPMSatisfiable(constraint, published)
+}
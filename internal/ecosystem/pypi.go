@@ -0,0 +1,37 @@
+package ecosystem
+
+import (
+	"github.com/Swayamyadav01/Deptakeover/internal/registry"
+	"github.com/Swayamyadav01/Deptakeover/internal/scanner"
+	"github.com/Swayamyadav01/Deptakeover/internal/version"
+)
+
+type pypiEcosystem struct{}
+
+func init() {
+	Register(pypiEcosystem{}, "py", "python")
+}
+
+func (pypiEcosystem) Name() string { return "pypi" }
+
+func (pypiEcosystem) FindManifests(repoPath string) []string {
+	return scanner.FindPythonDependencyFiles(repoPath)
+}
+
+func (pypiEcosystem) ExtractDependencies(manifestPath string) ([]scanner.Dependency, error) {
+	return scanner.ExtractPythonDependencies(manifestPath)
+}
+
+func (pypiEcosystem) PrivateRegistries(repoPath string) []scanner.PrivateRegistry {
+	return scanner.FindPythonPrivateRegistries(repoPath)
+}
+
+func (pypiEcosystem) NormalizeName(name string) string { return registry.NormalizePyPIName(name) }
+
+func (pypiEcosystem) CheckPackage(name string) registry.PackageInfo {
+	return registry.CheckPyPIPackageRisk(name)
+}
+
+func (pypiEcosystem) Satisfiable(constraint string, published []string) (bool, bool) {
+	return version.PEP440Satisfiable(constraint, published)
+}
@@ -0,0 +1,101 @@
+package ecosystem
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Swayamyadav01/Deptakeover/internal/registry"
+	"github.com/Swayamyadav01/Deptakeover/internal/scanner"
+)
+
+// transitiveDepth is the number of dependency levels below the manifests'
+// own dependencies that Analyze follows through registry metadata.
+var transitiveDepth int
+
+// SetDepth makes Analyze also check the dependencies of dependencies, up to
+// depth levels down, as declared by the latest release of each package on
+// the registry. 0 checks only what the manifests declare.
+func SetDepth(depth int) {
+	transitiveDepth = depth
+}
+
+// resolveTransitive walks the dependency graph breadth-first from the
+// packages already in analysis, checking each package once. Packages found
+// this way get the chain that pulls them in, starting from the manifest
+// that declares the first link, as "dependency_path". It returns the number
+// of packages looked up.
+func resolveTransitive(eco Ecosystem, analysis *Analysis, scan ScanResult, check func(string) registry.PackageInfo) int {
+	paths := make(map[string][]string)
+	for name, manifest := range rootManifests(eco, scan) {
+		paths[name] = []string{manifest, name}
+	}
+
+	frontier := append([]string{}, scan.Packages...)
+	lookups := 0
+	for level := 1; level <= transitiveDepth && len(frontier) > 0; level++ {
+		var next []string
+		for _, parent := range frontier {
+			deps := analysis.Packages[parent].Dependencies
+			names := make([]string, 0, len(deps))
+			for dep := range deps {
+				names = append(names, dep)
+			}
+			sort.Strings(names)
+
+			for _, dep := range names {
+				dep = eco.NormalizeName(dep)
+				if _, seen := paths[dep]; seen {
+					continue
+				}
+				if _, seen := analysis.Packages[dep]; seen {
+					continue
+				}
+				paths[dep] = append(append([]string{}, paths[parent]...), dep)
+				next = append(next, dep)
+			}
+		}
+		if len(next) == 0 {
+			break
+		}
+
+		// Through AnalyzeDependencyRisks so unknown lookups are retried the
+		// same as the manifests' own dependencies.
+		fmt.Printf("Checking %d transitive dependencies (depth %d)...\n", len(next), level)
+		for name, info := range registry.AnalyzeDependencyRisks(next, check) {
+			if info.Metadata == nil {
+				info.Metadata = make(map[string]interface{})
+			}
+			info.Metadata["dependency_path"] = paths[name]
+			info.Signals = append(info.Signals, "transitive_dependency")
+			analysis.Packages[name] = info
+		}
+		lookups += len(next)
+		frontier = next
+	}
+	return lookups
+}
+
+// rootManifests maps each package the manifests declare to the first
+// manifest, in path order, that declares it.
+func rootManifests(eco Ecosystem, scan ScanResult) map[string]string {
+	var manifests []string
+	for manifest := range scan.DependenciesByFile {
+		manifests = append(manifests, manifest)
+	}
+	sort.Strings(manifests)
+
+	roots := make(map[string]string)
+	for _, manifest := range manifests {
+		for _, dep := range scan.DependenciesByFile[manifest] {
+			name := dep.Name
+			if dep.Canonical != "" {
+				name = dep.Canonical
+			}
+			name = eco.NormalizeName(name)
+			if _, ok := roots[name]; !ok && (dep.FromRegistry() || dep.Kind == scanner.SpecWorkspace) {
+				roots[name] = manifest
+			}
+		}
+	}
+	return roots
+}
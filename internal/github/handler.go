@@ -4,11 +4,12 @@ import (
 	"archive/zip"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/Swayamyadav01/Deptakeover/internal/httpclient"
+
 	"github.com/go-git/go-git/v5"
 )
 
@@ -37,14 +38,14 @@ func downloadZip(repoURL, destPath string) error {
 	zipURL := strings.TrimSuffix(repoURL, ".git") + "/archive/refs/heads/main.zip"
 
 	// Try main branch
-	resp, err := http.Get(zipURL)
+	resp, err := httpclient.Default.Get(zipURL)
 	if err != nil || resp.StatusCode == 404 {
 		if resp != nil {
 			resp.Body.Close()
 		}
 		// Try master branch
 		zipURL = strings.TrimSuffix(repoURL, ".git") + "/archive/refs/heads/master.zip"
-		resp, err = http.Get(zipURL)
+		resp, err = httpclient.Default.Get(zipURL)
 	}
 
 	if err != nil {
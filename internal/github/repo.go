@@ -0,0 +1,129 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/Swayamyadav01/Deptakeover/internal/httpclient"
+	"github.com/Swayamyadav01/Deptakeover/internal/registry"
+)
+
+// apiURL is the base URL of the GitHub REST API.
+var apiURL = "https://api.github.com"
+
+// repoURLPattern matches the owner and repository of a github.com git URL in
+// any of the forms package managers accept: https, git+https, git+ssh,
+// git:// and scp-like git@github.com:owner/repo, with an optional pip
+// "@ref" suffix.
+var repoURLPattern = regexp.MustCompile(`^(?:git\+)?(?:(?:https?|ssh|git)://)?(?:[^@/]+@)?(?:www\.)?github\.com[:/]([A-Za-z0-9-]+)/([A-Za-z0-9._-]+?)(?:\.git)?/?(?:@[^#?]*)?(?:[#?].*)?$`)
+
+// ParseRepoURL returns the "owner/repo" name of a git URL hosted on GitHub.
+func ParseRepoURL(raw string) (string, bool) {
+	m := repoURLPattern.FindStringSubmatch(strings.TrimSpace(raw))
+	if m == nil {
+		return "", false
+	}
+	return m[1] + "/" + m[2], true
+}
+
+// CheckRepo reports whether a GitHub repository ("owner/repo") a dependency
+// is fetched from still exists. When the owner account is gone, anyone can
+// register it and recreate the repository (repo-jacking), so that is
+// reported as critical. A missing repository under an existing owner can
+// only be recreated by that owner and is reported as medium.
+func CheckRepo(fullName string) registry.PackageInfo {
+	result := registry.PackageInfo{
+		Status:   registry.StatusUnknown,
+		Package:  fullName,
+		Metadata: make(map[string]interface{}),
+	}
+	owner, _, _ := strings.Cut(fullName, "/")
+
+	var user struct {
+		Type string `json:"type"`
+	}
+	status, retries, err := apiGet("/users/"+url.PathEscape(owner), &user)
+	result.Retries += retries
+	if err != nil {
+		fmt.Printf("Warning: Error checking GitHub owner %s: %v\n", owner, err)
+		result.Error = err.Error()
+		return result
+	}
+	if status == http.StatusNotFound {
+		fmt.Printf("Info: GitHub owner is unclaimed: %s\n", owner)
+		result.Status = registry.StatusNotFound
+		result.RiskScore = 100
+		result.Severity = registry.SeverityCritical
+		result.Signals = []string{"github_owner_unclaimed"}
+		return result
+	}
+	result.Metadata["owner_type"] = user.Type
+
+	var repo struct {
+		FullName string `json:"full_name"`
+		Archived bool   `json:"archived"`
+	}
+	status, retries, err = apiGet("/repos/"+fullName, &repo)
+	result.Retries += retries
+	if err != nil {
+		fmt.Printf("Warning: Error checking GitHub repo %s: %v\n", fullName, err)
+		result.Error = err.Error()
+		return result
+	}
+	if status == http.StatusNotFound {
+		fmt.Printf("Info: GitHub repo not found: %s\n", fullName)
+		result.Status = registry.StatusNotFound
+		result.RiskScore = 50
+		result.Severity = registry.SeverityMedium
+		result.Signals = []string{"github_repo_missing"}
+		return result
+	}
+
+	result.Status = registry.StatusExists
+	result.Exists = true
+	if !strings.EqualFold(repo.FullName, fullName) {
+		// Renamed or transferred repositories redirect to their new home.
+		result.Signals = append(result.Signals, "github_repo_moved")
+		result.Metadata["moved_to"] = repo.FullName
+	}
+	if repo.Archived {
+		result.Signals = append(result.Signals, "github_repo_archived")
+	}
+	return result
+}
+
+// apiGet fetches a GitHub API path into v and returns the HTTP status and the
+// number of retries it took. A 404 is not an error. GITHUB_TOKEN is sent when
+// set, since unauthenticated clients only get 60 requests per hour.
+func apiGet(path string, v interface{}) (int, int, error) {
+	ctx, retries := httpclient.WithRetryCounter(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL+path, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := httpclient.Default.Do(req)
+	if err != nil {
+		return 0, int(*retries), err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.StatusCode, int(*retries), json.NewDecoder(resp.Body).Decode(v)
+	case http.StatusNotFound:
+		return resp.StatusCode, int(*retries), nil
+	default:
+		return resp.StatusCode, int(*retries), fmt.Errorf("unexpected HTTP status %d", resp.StatusCode)
+	}
+}
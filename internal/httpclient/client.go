@@ -0,0 +1,26 @@
+package httpclient
+
+import (
+	"net/http"
+	"time"
+)
+
+// NewTransport returns a pooled transport suitable for many small API calls
+// against a handful of hosts.
+func NewTransport() *http.Transport {
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   32,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 15 * time.Second,
+	}
+}
+
+// Default is the shared client for GitHub API and download requests. It has
+// no overall timeout so that large archive downloads can finish; each attempt
+// is bounded by the transport's header timeout instead.
+var Default = &http.Client{
+	Transport: NewRetryTransport(NewTransport()),
+}
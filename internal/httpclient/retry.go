@@ -0,0 +1,154 @@
+// Package httpclient provides the HTTP plumbing shared by the registry
+// checkers and the GitHub API calls: connection pooling plus retries with
+// backoff for rate limits and transient server errors.
+package httpclient
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// RetryTransport retries idempotent requests that fail with a network error,
+// HTTP 429 or a 5xx gateway/availability error. Waits use exponential
+// backoff with jitter unless the server says how long to wait through
+// Retry-After or GitHub's X-RateLimit-Reset.
+type RetryTransport struct {
+	Base http.RoundTripper
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	// BaseDelay is the backoff before the first retry; it doubles each time.
+	BaseDelay time.Duration
+	// MaxDelay caps a single backoff wait.
+	MaxDelay time.Duration
+	// MaxWait is the longest server-requested wait that will be honoured.
+	// Responses asking for a longer wait are returned to the caller as is.
+	MaxWait time.Duration
+}
+
+// NewRetryTransport returns a RetryTransport around base with the defaults
+// used throughout deptakeover.
+func NewRetryTransport(base http.RoundTripper) *RetryTransport {
+	return &RetryTransport{
+		Base:       base,
+		MaxRetries: 3,
+		BaseDelay:  500 * time.Millisecond,
+		MaxDelay:   10 * time.Second,
+		MaxWait:    2 * time.Minute,
+	}
+}
+
+type retryCounterKey struct{}
+
+// WithRetryCounter returns a context that makes RetryTransport count the
+// retries it performs for requests carrying it.
+func WithRetryCounter(ctx context.Context) (context.Context, *int32) {
+	counter := new(int32)
+	return context.WithValue(ctx, retryCounterKey{}, counter), counter
+}
+
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return t.Base.RoundTrip(req)
+	}
+
+	counter, _ := req.Context().Value(retryCounterKey{}).(*int32)
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.Base.RoundTrip(req)
+		if attempt >= t.MaxRetries || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		var wait time.Duration
+		if err != nil {
+			wait = t.backoff(attempt)
+		} else {
+			var retry bool
+			wait, retry = t.retryDelay(resp, attempt)
+			if !retry {
+				return resp, nil
+			}
+			resp.Body.Close()
+		}
+
+		if counter != nil {
+			atomic.AddInt32(counter, 1)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryDelay decides whether resp should be retried and how long to wait.
+func (t *RetryTransport) retryDelay(resp *http.Response, attempt int) (time.Duration, bool) {
+	rateLimited := resp.StatusCode == http.StatusForbidden &&
+		(resp.Header.Get("Retry-After") != "" || resp.Header.Get("X-RateLimit-Remaining") == "0")
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests, rateLimited:
+	case resp.StatusCode == http.StatusInternalServerError,
+		resp.StatusCode == http.StatusBadGateway,
+		resp.StatusCode == http.StatusServiceUnavailable,
+		resp.StatusCode == http.StatusGatewayTimeout:
+	default:
+		return 0, false
+	}
+
+	if wait, ok := serverWait(resp); ok {
+		if wait > t.MaxWait {
+			return 0, false
+		}
+		return wait, true
+	}
+	return t.backoff(attempt), true
+}
+
+func (t *RetryTransport) backoff(attempt int) time.Duration {
+	d := t.BaseDelay << attempt
+	if d > t.MaxDelay || d <= 0 {
+		d = t.MaxDelay
+	}
+	// Equal jitter: half fixed, half random, so concurrent workers spread out.
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// serverWait reads Retry-After (seconds or HTTP date) or, when the GitHub
+// quota is exhausted, X-RateLimit-Reset (unix seconds).
+func serverWait(resp *http.Response) (time.Duration, bool) {
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil {
+			return time.Duration(secs) * time.Second, true
+		}
+		if at, err := http.ParseTime(v); err == nil {
+			return nonNegative(time.Until(at)), true
+		}
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if v := resp.Header.Get("X-RateLimit-Reset"); v != "" {
+			if unix, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return nonNegative(time.Until(time.Unix(unix, 0))) + time.Second, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
@@ -0,0 +1,198 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		// responses are served in order; the last one repeats.
+		responses []func(w http.ResponseWriter)
+		status    int
+		attempts  int32
+	}{
+		{
+			name:      "429 with Retry-After",
+			responses: []func(http.ResponseWriter){respond(429, "Retry-After", "0"), respond(200)},
+			status:    200,
+			attempts:  2,
+		},
+		{
+			name:      "5xx with backoff",
+			responses: []func(http.ResponseWriter){respond(503), respond(502), respond(200)},
+			status:    200,
+			attempts:  3,
+		},
+		{
+			name:      "GitHub rate limit",
+			responses: []func(http.ResponseWriter){respond(403, "X-RateLimit-Remaining", "0", "Retry-After", "0"), respond(200)},
+			status:    200,
+			attempts:  2,
+		},
+		{
+			name:      "403 without rate limit headers",
+			responses: []func(http.ResponseWriter){respond(403), respond(200)},
+			status:    403,
+			attempts:  1,
+		},
+		{
+			name:      "404",
+			responses: []func(http.ResponseWriter){respond(404), respond(200)},
+			status:    404,
+			attempts:  1,
+		},
+		{
+			name:      "Retry-After beyond MaxWait",
+			responses: []func(http.ResponseWriter){respond(429, "Retry-After", "3600"), respond(200)},
+			status:    429,
+			attempts:  1,
+		},
+		{
+			name:      "retries exhausted",
+			responses: []func(http.ResponseWriter){respond(500)},
+			status:    500,
+			attempts:  4,
+		},
+		{
+			name:      "POST is not retried",
+			method:    http.MethodPost,
+			responses: []func(http.ResponseWriter){respond(503), respond(200)},
+			status:    503,
+			attempts:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(atomic.AddInt32(&attempts, 1))
+				tt.responses[min(n, len(tt.responses))-1](w)
+			}))
+			defer srv.Close()
+
+			transport := NewRetryTransport(http.DefaultTransport)
+			transport.BaseDelay = time.Millisecond
+			transport.MaxDelay = 5 * time.Millisecond
+			transport.MaxWait = time.Minute
+			client := &http.Client{Transport: transport}
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			ctx, retries := WithRetryCounter(context.Background())
+			req, err := http.NewRequestWithContext(ctx, method, srv.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("Do: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+			if attempts != tt.attempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.attempts)
+			}
+			if *retries != tt.attempts-1 {
+				t.Errorf("retry counter = %d, want %d", *retries, tt.attempts-1)
+			}
+		})
+	}
+}
+
+// respond returns a handler writing status with the given header pairs.
+func respond(status int, headers ...string) func(http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		for i := 0; i+1 < len(headers); i += 2 {
+			w.Header().Set(headers[i], headers[i+1])
+		}
+		w.WriteHeader(status)
+	}
+}
+
+func TestServerWait(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		min, max time.Duration
+		ok       bool
+	}{
+		{"Retry-After seconds", map[string]string{"Retry-After": "5"}, 5 * time.Second, 5 * time.Second, true},
+		{"Retry-After date", map[string]string{"Retry-After": now.Add(30 * time.Second).UTC().Format(http.TimeFormat)}, 28 * time.Second, 30 * time.Second, true},
+		{"Retry-After date in the past", map[string]string{"Retry-After": now.Add(-time.Hour).UTC().Format(http.TimeFormat)}, 0, 0, true},
+		{"Retry-After unparsable", map[string]string{"Retry-After": "soon"}, 0, 0, false},
+		{"X-RateLimit-Reset", map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": strconv.FormatInt(now.Add(10*time.Second).Unix(), 10)}, 9 * time.Second, 11 * time.Second, true},
+		{"X-RateLimit-Reset in the past", map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": strconv.FormatInt(now.Add(-time.Minute).Unix(), 10)}, time.Second, time.Second, true},
+		{"X-RateLimit-Reset with quota left", map[string]string{"X-RateLimit-Remaining": "10", "X-RateLimit-Reset": strconv.FormatInt(now.Unix(), 10)}, 0, 0, false},
+		{"Retry-After wins", map[string]string{"Retry-After": "2", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": strconv.FormatInt(now.Add(time.Hour).Unix(), 10)}, 2 * time.Second, 2 * time.Second, true},
+		{"no headers", nil, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		resp := &http.Response{Header: make(http.Header)}
+		for k, v := range tt.headers {
+			resp.Header.Set(k, v)
+		}
+		wait, ok := serverWait(resp)
+		if ok != tt.ok || wait < tt.min || wait > tt.max {
+			t.Errorf("%s: serverWait = %v, %v, want %v..%v, %v", tt.name, wait, ok, tt.min, tt.max, tt.ok)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	transport := &RetryTransport{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{0, 50 * time.Millisecond, 100 * time.Millisecond},
+		{1, 100 * time.Millisecond, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond, 800 * time.Millisecond},
+		// Capped at MaxDelay, jitter included.
+		{4, 500 * time.Millisecond, time.Second},
+		{10, 500 * time.Millisecond, time.Second},
+		// The shift overflows.
+		{70, 500 * time.Millisecond, time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			if d := transport.backoff(tt.attempt); d < tt.min || d > tt.max {
+				t.Errorf("backoff(%d) = %v, want %v..%v", tt.attempt, d, tt.min, tt.max)
+				break
+			}
+		}
+	}
+}
+
+func TestRetryTransportCancelledWait(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	_, err := (&http.Client{Transport: NewRetryTransport(http.DefaultTransport)}).Do(req)
+	if err == nil || !strings.Contains(err.Error(), "deadline exceeded") {
+		t.Errorf("Do = %v, want a deadline error", err)
+	}
+}
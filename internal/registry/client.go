@@ -0,0 +1,104 @@
+package registry
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Swayamyadav01/Deptakeover/internal/httpclient"
+)
+
+// Default per-host request rates, in requests per second. Hosts not listed
+// here are not rate limited.
+var defaultRateLimits = map[string]float64{
+	"registry.npmjs.org": 25,
+	"pypi.org":           15,
+	"packagist.org":      10,
+	"repo.packagist.org": 10,
+}
+
+var (
+	limitersMu sync.Mutex
+	limiters   = make(map[string]*hostLimiter)
+)
+
+// httpClient is shared by every registry check so that connections are
+// pooled across lookups instead of being re-established per package. Every
+// attempt, retries included, goes through the per-host rate limiter.
+var httpClient = &http.Client{
+	Timeout:   2 * time.Minute,
+	Transport: newRegistryTransport(),
+}
+
+func newRegistryTransport() http.RoundTripper {
+	t := httpclient.NewRetryTransport(&rateLimitedTransport{base: httpclient.NewTransport()})
+	t.MaxWait = 30 * time.Second
+	return t
+}
+
+// fetch performs a GET against a registry and reports how many retries the
+// transport needed to get an answer.
+func fetch(url string) (*http.Response, int, error) {
+	ctx, retries := httpclient.WithRetryCounter(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := httpClient.Do(req)
+	return resp, int(*retries), err
+}
+
+func limiterFor(host string) *hostLimiter {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	if l, exists := limiters[host]; exists {
+		return l
+	}
+
+	var l *hostLimiter
+	if rate, ok := defaultRateLimits[host]; ok {
+		l = newHostLimiter(rate)
+	}
+	limiters[host] = l
+	return l
+}
+
+// rateLimitedTransport delays each request until its host's limiter allows it.
+type rateLimitedTransport struct {
+	base http.RoundTripper
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if l := limiterFor(req.URL.Hostname()); l != nil {
+		l.wait()
+	}
+	return t.base.RoundTrip(req)
+}
+
+// hostLimiter spaces requests evenly so that no more than the configured
+// rate is sent to a host.
+type hostLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newHostLimiter(perSecond float64) *hostLimiter {
+	return &hostLimiter{interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+func (l *hostLimiter) wait() {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	time.Sleep(delay)
+}
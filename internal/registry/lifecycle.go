@@ -0,0 +1,100 @@
+package registry
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Risk scores for packages that still exist but that their maintainers have
+// deprecated, abandoned or withdrawn. Each gets 10 more when no replacement
+// is named: with nowhere to migrate to, the package is the likelier to be
+// removed and its name freed.
+const (
+	riskInactive          = 30
+	riskLatestDeprecated  = 40
+	riskLatestYanked      = 40
+	riskArchived          = 40
+	riskAllDeprecated     = 50
+	riskAbandoned         = 50
+	riskAllYanked         = 50
+	riskProjectDeprecated = 50
+	riskQuarantined       = 60
+)
+
+// lifecycleSignals are the signals set by markStale.
+var lifecycleSignals = map[string]bool{
+	"deprecated_on_npm":           true,
+	"all_versions_deprecated":     true,
+	"abandoned_on_packagist":      true,
+	"latest_release_yanked":       true,
+	"all_releases_yanked":         true,
+	"inactive_development_status": true,
+	"project_archived_on_pypi":    true,
+	"project_deprecated_on_pypi":  true,
+	"project_quarantined_on_pypi": true,
+}
+
+// replacementPattern finds the package a deprecation or yank message points
+// users to: "use foo instead", "moved to @scope/foo", "replaced by foo".
+var replacementPattern = regexp.MustCompile("(?i)\\b(?:use|replaced by|moved to|renamed to|switch to|migrate to|in favou?r of|superseded by)\\s+[`\"']?(@?[A-Za-z0-9][\\w.-]*(?:/[\\w.-]+)?)")
+
+// replacementStopWords are words the pattern catches that are not packages,
+// as in "use at your own risk".
+var replacementStopWords = map[string]bool{
+	"a": true, "an": true, "at": true, "it": true, "the": true, "this": true,
+	"that": true, "of": true, "with": true, "your": true, "our": true,
+	"another": true, "latest": true, "version": true, "instead": true,
+}
+
+// IsStale reports whether a package that exists carries a lifecycle signal:
+// deprecated, abandoned, yanked or archived.
+func IsStale(info PackageInfo) bool {
+	if !info.Exists {
+		return false
+	}
+	for _, s := range info.Signals {
+		if lifecycleSignals[s] {
+			return true
+		}
+	}
+	return false
+}
+
+// markStale records a lifecycle signal on an existing package, raising its
+// risk score to the signal's. replacement is the successor package if the
+// registry names one; otherwise it is looked for in message.
+func markStale(result *PackageInfo, signal string, score int, message, replacement string) {
+	if replacement == "" {
+		replacement = replacementFrom(message)
+	}
+	if replacement == "" {
+		score += 10
+	}
+
+	result.Signals = append(result.Signals, signal)
+	if score > result.RiskScore {
+		result.RiskScore = score
+	}
+	if result.Severity == "" {
+		result.Severity = SeverityMedium
+	}
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]interface{})
+	}
+	if message != "" {
+		result.Metadata["lifecycle_message"] = message
+	}
+	if replacement != "" {
+		result.Metadata["replacement"] = replacement
+	}
+}
+
+func replacementFrom(message string) string {
+	for _, m := range replacementPattern.FindAllStringSubmatch(message, -1) {
+		name := strings.TrimRight(m[1], ".-")
+		if name != "" && !replacementStopWords[strings.ToLower(name)] {
+			return name
+		}
+	}
+	return ""
+}
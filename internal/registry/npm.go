@@ -4,77 +4,248 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
-	"time"
+	"net/url"
+	"sort"
+	"strings"
 )
 
-type NPMPackageInfo struct {
-	Exists    bool
-	RiskScore int
-	Signals   []string
-	Metadata  map[string]interface{}
-	Package   string
+// npmRegistryURL is the base URL of the public npm registry.
+var npmRegistryURL = "https://registry.npmjs.org"
+
+// npmSecurityHoldingVersion is the placeholder version npm publishes after
+// taking down a malicious package. The name stays reserved by npm.
+const npmSecurityHoldingVersion = "0.0.1-security"
+
+// npmPackument is the subset of an npm package document we inspect.
+type npmPackument struct {
+	Name        string                     `json:"name"`
+	Description string                     `json:"description"`
+	DistTags    map[string]string          `json:"dist-tags"`
+	Versions    map[string]json.RawMessage `json:"versions"`
+	Maintainers []npmPerson                `json:"maintainers"`
+	// Time maps versions to publish dates, except for "unpublished" which
+	// holds an npmUnpublished object.
+	Time map[string]json.RawMessage `json:"time"`
 }
 
-func CheckNPMPackageRisk(packageName string) NPMPackageInfo {
-	result := NPMPackageInfo{
-		Package:   packageName,
-		Exists:    false,
-		RiskScore: 0,
-		Signals:   []string{},
-		Metadata:  make(map[string]interface{}),
-	}
+type npmUnpublished struct {
+	Time        string      `json:"time"`
+	Versions    []string    `json:"versions"`
+	Maintainers []npmPerson `json:"maintainers"`
+}
 
-	url := fmt.Sprintf("https://registry.npmjs.org/%s", packageName)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		fmt.Printf("Error creating request for %s: %v\n", packageName, err)
-		return result
-	}
+type npmPerson struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
 
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-	}
+func CheckNPMPackageRisk(packageName string) PackageInfo {
+	result := newPackageInfo(packageName)
 
-	resp, err := client.Do(req)
+	resp, retries, err := fetch(npmRegistryURL + "/" + url.PathEscape(packageName))
+	result.Retries = retries
 	if err != nil {
 		fmt.Printf("Warning: Error fetching %s from npm registry: %v\n", packageName, err)
+		result.Error = err.Error()
 		return result
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == 404 {
 		fmt.Printf("Info: Package not found on npm: %s\n", packageName)
+		result.Status = StatusNotFound
 		result.RiskScore = 100
+		result.Severity = SeverityHigh
 		result.Signals = []string{"not_found_on_npm"}
 		return result
 	}
 
 	if resp.StatusCode == 200 {
+		result.Status = StatusExists
 		result.Exists = true
 		result.RiskScore = 0
 
 		body, _ := io.ReadAll(resp.Body)
-		var data map[string]interface{}
-		if err := json.Unmarshal(body, &data); err == nil {
+		var doc npmPackument
+		if err := json.Unmarshal(body, &doc); err == nil {
 			result.Metadata = map[string]interface{}{
-				"name":        data["name"],
-				"description": data["description"],
+				"name":        doc.Name,
+				"description": doc.Description,
 				"repository":  "npm",
 			}
+			applyNPMPublishState(&result, doc)
+			for v := range doc.Versions {
+				result.Versions = append(result.Versions, v)
+			}
+			sort.Strings(result.Versions)
+			result.Dependencies = npmLatestDependencies(doc)
+			if result.Exists {
+				applyNPMDeprecation(&result, doc)
+			}
 		}
 		return result
 	}
 
 	fmt.Printf("Warning: Unexpected status %d for %s\n", resp.StatusCode, packageName)
+	result.Error = fmt.Sprintf("unexpected HTTP status %d", resp.StatusCode)
+	return result
+}
+
+// NPMScope returns the scope of a scoped package name ("@babel/core" ->
+// "@babel").
+func NPMScope(packageName string) (string, bool) {
+	if !strings.HasPrefix(packageName, "@") {
+		return "", false
+	}
+	scope, _, found := strings.Cut(packageName, "/")
+	return scope, found && len(scope) > 1
+}
+
+// CheckNPMScope reports whether an npm scope is owned by a user or an
+// organization. An unclaimed scope lets anyone publish every package name
+// under it, so it is reported with critical severity.
+func CheckNPMScope(scope string) PackageInfo {
+	result := newPackageInfo(scope)
+	name := url.PathEscape(strings.TrimPrefix(scope, "@"))
+
+	// Organizations and users have separate package listing endpoints; the
+	// scope is claimed if either of them knows it.
+	for _, kind := range []string{"org", "user"} {
+		resp, retries, err := fetch(fmt.Sprintf("%s/-/%s/%s/package", npmRegistryURL, kind, name))
+		result.Retries += retries
+		if err != nil {
+			fmt.Printf("Warning: Error checking npm scope %s: %v\n", scope, err)
+			result.Error = err.Error()
+			return result
+		}
+		resp.Body.Close()
+
+		switch resp.StatusCode {
+		case 200:
+			result.Status = StatusExists
+			result.Exists = true
+			result.Metadata["owner_type"] = kind
+			return result
+		case 404:
+			continue
+		default:
+			fmt.Printf("Warning: Unexpected status %d for npm scope %s\n", resp.StatusCode, scope)
+			result.Error = fmt.Sprintf("unexpected HTTP status %d", resp.StatusCode)
+			return result
+		}
+	}
+
+	fmt.Printf("Info: npm scope is unclaimed: %s\n", scope)
+	result.Status = StatusNotFound
+	result.RiskScore = 100
+	result.Severity = SeverityCritical
+	result.Signals = []string{"scope_unclaimed_on_npm"}
 	return result
 }
 
-func AnalyzeNPMDependencyRisks(packages []string) map[string]NPMPackageInfo {
-	results := make(map[string]NPMPackageInfo)
-	for _, pkg := range packages {
-		fmt.Printf("Analyzing %s...\n", pkg)
-		results[pkg] = CheckNPMPackageRisk(pkg)
+// applyNPMPublishState flags packages that answer 200 but have nothing
+// installable: fully unpublished packages and packages with no versions are
+// claimable, security holding packages are reserved by npm.
+func applyNPMPublishState(result *PackageInfo, doc npmPackument) {
+	if raw, ok := doc.Time["unpublished"]; ok {
+		var unpublished npmUnpublished
+		if err := json.Unmarshal(raw, &unpublished); err != nil {
+			fmt.Printf("Warning: Invalid unpublish record for %s: %v\n", result.Package, err)
+		}
+
+		fmt.Printf("Info: Package unpublished from npm: %s\n", result.Package)
+		result.Status = StatusNotFound
+		result.Exists = false
+		result.RiskScore = 100
+		result.Severity = SeverityHigh
+		result.Signals = append(result.Signals, "unpublished_on_npm")
+
+		maintainers := unpublished.Maintainers
+		if len(maintainers) == 0 {
+			maintainers = doc.Maintainers
+		}
+		result.Metadata["unpublished_at"] = unpublished.Time
+		result.Metadata["unpublished_versions"] = unpublished.Versions
+		result.Metadata["former_maintainers"] = npmPersonNames(maintainers)
+		return
+	}
+
+	if len(doc.Versions) == 0 {
+		fmt.Printf("Info: Package has no versions on npm: %s\n", result.Package)
+		result.Status = StatusNotFound
+		result.Exists = false
+		result.RiskScore = 100
+		result.Severity = SeverityHigh
+		result.Signals = append(result.Signals, "no_versions_on_npm")
+		result.Metadata["former_maintainers"] = npmPersonNames(doc.Maintainers)
+		return
+	}
+
+	if doc.DistTags["latest"] == npmSecurityHoldingVersion {
+		// The name cannot be claimed, but whatever was taken down may
+		// still be installed from lockfiles and caches: worth a look, not a
+		// takeover target.
+		fmt.Printf("Info: Package is an npm security holding: %s\n", result.Package)
+		result.RiskScore = 40
+		result.Severity = SeverityMedium
+		result.Signals = append(result.Signals, "security_holding_package")
+	}
+}
+
+// applyNPMDeprecation flags a package whose latest version, or every
+// version, is deprecated, with the deprecation message.
+func applyNPMDeprecation(result *PackageInfo, doc npmPackument) {
+	latest := doc.DistTags["latest"]
+	var latestMessage string
+	deprecated := 0
+	for version, raw := range doc.Versions {
+		var v struct {
+			// Deprecated is a message, or false once undeprecated.
+			Deprecated interface{} `json:"deprecated"`
+		}
+		json.Unmarshal(raw, &v)
+		if message, ok := v.Deprecated.(string); ok && message != "" {
+			deprecated++
+			if version == latest {
+				latestMessage = message
+			}
+		}
+	}
+
+	switch {
+	case deprecated == len(doc.Versions):
+		if latestMessage == "" {
+			latestMessage = "all versions deprecated"
+		}
+		fmt.Printf("Info: Every version of %s is deprecated on npm\n", result.Package)
+		markStale(result, "all_versions_deprecated", riskAllDeprecated, latestMessage, "")
+	case latestMessage != "":
+		fmt.Printf("Info: Latest version of %s is deprecated on npm\n", result.Package)
+		markStale(result, "deprecated_on_npm", riskLatestDeprecated, latestMessage, "")
+	}
+}
+
+// npmLatestDependencies returns the registry dependencies of the latest
+// version. Git, URL, file and alias specs are left out.
+func npmLatestDependencies(doc npmPackument) map[string]string {
+	var latest struct {
+		Dependencies map[string]string `json:"dependencies"`
+	}
+	json.Unmarshal(doc.Versions[doc.DistTags["latest"]], &latest)
+
+	deps := make(map[string]string)
+	for name, spec := range latest.Dependencies {
+		if !strings.ContainsAny(spec, ":/") {
+			deps[name] = spec
+		}
+	}
+	return deps
+}
+
+func npmPersonNames(people []npmPerson) []string {
+	names := make([]string, 0, len(people))
+	for _, p := range people {
+		names = append(names, p.Name)
 	}
-	return results
+	return names
 }
@@ -4,61 +4,49 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
-	"time"
+	"net/url"
+	"sort"
+	"strings"
 )
 
-type PackagistPackageInfo struct {
-	Exists    bool
-	RiskScore int
-	Signals   []string
-	Metadata  map[string]interface{}
-	Package   string
-}
+// packagistURL is the base URL of Packagist.
+var packagistURL = "https://packagist.org"
 
 type PackagistPackageJSON struct {
 	Package struct {
 		Name        string `json:"name"`
 		Description string `json:"description"`
 		Repository  string `json:"repository"`
+		// Abandoned is true, or the name of the replacement package.
+		Abandoned interface{}                `json:"abandoned"`
+		Versions  map[string]json.RawMessage `json:"versions"`
 	} `json:"package"`
 }
 
-func CheckPackagistPackageRisk(packageName string) PackagistPackageInfo {
-	result := PackagistPackageInfo{
-		Package:   packageName,
-		Exists:    false,
-		RiskScore: 0,
-		Signals:   []string{},
-		Metadata:  make(map[string]interface{}),
-	}
-
-	url := fmt.Sprintf("https://packagist.org/packages/%s.json", packageName)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		fmt.Printf("Error creating request for %s: %v\n", packageName, err)
-		return result
-	}
-
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-	}
+// CheckPackagistPackageRisk looks a vendor/package name up on Packagist.
+func CheckPackagistPackageRisk(packageName string) PackageInfo {
+	result := newPackageInfo(packageName)
 
-	resp, err := client.Do(req)
+	resp, retries, err := fetch(fmt.Sprintf("%s/packages/%s.json", packagistURL, packageName))
+	result.Retries = retries
 	if err != nil {
 		fmt.Printf("Warning: Error fetching %s from Packagist: %v\n", packageName, err)
+		result.Error = err.Error()
 		return result
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == 404 {
 		fmt.Printf("Info: Package not found on Packagist: %s\n", packageName)
+		result.Status = StatusNotFound
 		result.RiskScore = 100
+		result.Severity = SeverityHigh
 		result.Signals = []string{"not_found_on_packagist"}
 		return result
 	}
 
 	if resp.StatusCode == 200 {
+		result.Status = StatusExists
 		result.Exists = true
 		result.RiskScore = 0
 
@@ -70,19 +58,108 @@ func CheckPackagistPackageRisk(packageName string) PackagistPackageInfo {
 				"description": data.Package.Description,
 				"repository":  data.Package.Repository,
 			}
+			for v := range data.Package.Versions {
+				result.Versions = append(result.Versions, v)
+			}
+			sort.Strings(result.Versions)
+			result.Dependencies = packagistLatestRequire(data.Package.Versions)
+			switch abandoned := data.Package.Abandoned.(type) {
+			case bool:
+				if abandoned {
+					fmt.Printf("Info: Package is abandoned on Packagist: %s\n", packageName)
+					markStale(&result, "abandoned_on_packagist", riskAbandoned, "", "")
+				}
+			case string:
+				fmt.Printf("Info: Package is abandoned on Packagist: %s (use %s)\n", packageName, abandoned)
+				markStale(&result, "abandoned_on_packagist", riskAbandoned, "", abandoned)
+			}
 		}
 		return result
 	}
 
 	fmt.Printf("Warning: Unexpected status %d for %s\n", resp.StatusCode, packageName)
+	result.Error = fmt.Sprintf("unexpected HTTP status %d", resp.StatusCode)
 	return result
 }
 
-func AnalyzePackagistDependencyRisks(packages []string) map[string]PackagistPackageInfo {
-	results := make(map[string]PackagistPackageInfo)
-	for _, pkg := range packages {
-		fmt.Printf("Analyzing %s...\n", pkg)
-		results[pkg] = CheckPackagistPackageRisk(pkg)
+// packagistLatestRequire returns the package requirements of the most
+// recently released stable version. Platform requirements (php, ext-*) are
+// left out.
+func packagistLatestRequire(versions map[string]json.RawMessage) map[string]string {
+	type packagistVersion struct {
+		Time    string            `json:"time"`
+		Require map[string]string `json:"require"`
+	}
+
+	var latest packagistVersion
+	for name, raw := range versions {
+		if strings.HasPrefix(name, "dev-") || strings.HasSuffix(name, "-dev") {
+			continue
+		}
+		var v packagistVersion
+		// Times are ISO 8601, so they sort as strings.
+		if json.Unmarshal(raw, &v) == nil && v.Time > latest.Time {
+			latest = v
+		}
+	}
+
+	deps := make(map[string]string)
+	for name, constraint := range latest.Require {
+		if strings.Contains(name, "/") {
+			deps[strings.ToLower(name)] = constraint
+		}
+	}
+	return deps
+}
+
+// PackagistVendor returns the vendor of a package name ("symfony/console"
+// -> "symfony").
+func PackagistVendor(packageName string) (string, bool) {
+	vendor, _, found := strings.Cut(packageName, "/")
+	return vendor, found && vendor != ""
+}
+
+// CheckPackagistVendor lists the packages published under a vendor. A vendor
+// without packages is unclaimed: whoever submits the first package under it
+// owns every name in it, so it is reported with critical severity. A vendor
+// with packages is reserved for their maintainers.
+func CheckPackagistVendor(vendor string) PackageInfo {
+	result := newPackageInfo(vendor)
+	resp, retries, err := fetch(packagistURL + "/packages/list.json?vendor=" + url.QueryEscape(vendor))
+	result.Retries = retries
+	if err != nil {
+		fmt.Printf("Warning: Error checking Packagist vendor %s: %v\n", vendor, err)
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		fmt.Printf("Warning: Unexpected status %d for Packagist vendor %s\n", resp.StatusCode, vendor)
+		result.Error = fmt.Sprintf("unexpected HTTP status %d", resp.StatusCode)
+		return result
 	}
-	return results
+
+	var list struct {
+		PackageNames []string `json:"packageNames"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if err := json.Unmarshal(body, &list); err != nil {
+		result.Error = fmt.Sprintf("invalid vendor listing: %v", err)
+		return result
+	}
+
+	result.Metadata["package_count"] = len(list.PackageNames)
+	if len(list.PackageNames) == 0 {
+		fmt.Printf("Info: Packagist vendor is unclaimed: %s\n", vendor)
+		result.Status = StatusNotFound
+		result.RiskScore = 100
+		result.Severity = SeverityCritical
+		result.Signals = []string{"vendor_unclaimed_on_packagist"}
+	} else {
+		result.Status = StatusExists
+		result.Exists = true
+	}
+
+	return result
 }
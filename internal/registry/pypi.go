@@ -4,60 +4,53 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
-	"time"
+	"regexp"
+	"sort"
+	"strings"
 )
 
-type PyPIPackageInfo struct {
-	Exists    bool
-	RiskScore int
-	Signals   []string
-	Metadata  map[string]interface{}
-	Package   string
+// pypiNameSeparators matches the runs of separators PEP 503 folds together.
+var pypiNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// NormalizePyPIName returns the PEP 503 canonical form of a project name, so
+// that Foo_Bar, foo.bar and foo-bar all refer to the same package.
+func NormalizePyPIName(name string) string {
+	return strings.ToLower(pypiNameSeparators.ReplaceAllString(name, "-"))
 }
 
-func CheckPyPIPackageRisk(packageName string) PyPIPackageInfo {
-	result := PyPIPackageInfo{
-		Package:   packageName,
-		Exists:    false,
-		RiskScore: 0,
-		Signals:   []string{},
-		Metadata:  make(map[string]interface{}),
-	}
+func CheckPyPIPackageRisk(packageName string) PackageInfo {
+	result := newPackageInfo(packageName)
 
 	// Skip special entries like "-e ."
 	if packageName == "-e ." {
+		result.Status = StatusNotFound
 		result.RiskScore = 100
+		result.Severity = SeverityHigh
 		result.Signals = []string{"not_found_on_pypi"}
 		return result
 	}
 
-	url := fmt.Sprintf("https://pypi.org/pypi/%s/json", packageName)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		fmt.Printf("Error creating request for %s: %v\n", packageName, err)
-		return result
-	}
-
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-	}
-
-	resp, err := client.Do(req)
+	url := fmt.Sprintf("https://pypi.org/pypi/%s/json", NormalizePyPIName(packageName))
+	resp, retries, err := fetch(url)
+	result.Retries = retries
 	if err != nil {
 		fmt.Printf("Warning: Error fetching %s from PyPI: %v\n", packageName, err)
+		result.Error = err.Error()
 		return result
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == 404 {
 		fmt.Printf("Info: Package not found on PyPI: %s\n", packageName)
+		result.Status = StatusNotFound
 		result.RiskScore = 100
+		result.Severity = SeverityHigh
 		result.Signals = []string{"not_found_on_pypi"}
 		return result
 	}
 
 	if resp.StatusCode == 200 {
+		result.Status = StatusExists
 		result.Exists = true
 		result.RiskScore = 0
 
@@ -70,20 +63,95 @@ func CheckPyPIPackageRisk(packageName string) PyPIPackageInfo {
 					"description": info["summary"],
 					"repository":  info["home_page"],
 				}
+				applyPyPILifecycle(&result, data, info)
+				result.Dependencies = pypiRequiresDist(info)
+			}
+			releases, _ := data["releases"].(map[string]interface{})
+			for v := range releases {
+				result.Versions = append(result.Versions, v)
 			}
+			sort.Strings(result.Versions)
 		}
 		return result
 	}
 
 	fmt.Printf("Warning: Unexpected status %d for %s\n", resp.StatusCode, packageName)
+	result.Error = fmt.Sprintf("unexpected HTTP status %d", resp.StatusCode)
 	return result
 }
 
-func AnalyzePyPIDependencyRisks(packages []string) map[string]PyPIPackageInfo {
-	results := make(map[string]PyPIPackageInfo)
-	for _, pkg := range packages {
-		fmt.Printf("Analyzing %s...\n", pkg)
-		results[pkg] = CheckPyPIPackageRisk(pkg)
+// requiresDistName matches the project name at the start of a Requires-Dist
+// entry.
+var requiresDistName = regexp.MustCompile(`^([A-Za-z0-9](?:[A-Za-z0-9._-]*[A-Za-z0-9])?)\s*(?:\[[^\]]*\])?\s*`)
+
+// pypiRequiresDist returns the requirements of the latest release, except
+// those only installed with an extra.
+func pypiRequiresDist(info map[string]interface{}) map[string]string {
+	deps := make(map[string]string)
+	requires, _ := info["requires_dist"].([]interface{})
+	for _, r := range requires {
+		line, _ := r.(string)
+		spec, marker, _ := strings.Cut(line, ";")
+		if strings.Contains(marker, "extra") {
+			continue
+		}
+		if m := requiresDistName.FindStringSubmatch(spec); m != nil {
+			constraint := strings.Trim(strings.TrimSpace(spec[len(m[0]):]), "()")
+			deps[m[1]] = strings.Join(strings.Fields(constraint), "")
+		}
+	}
+	return deps
+}
+
+// applyPyPILifecycle flags yanked releases, PEP 792 project status markers
+// and the "Development Status :: 7 - Inactive" classifier.
+func applyPyPILifecycle(result *PackageInfo, data, info map[string]interface{}) {
+	status, _ := data["project-status"].(map[string]interface{})
+	if status == nil {
+		status, _ = info["project_status"].(map[string]interface{})
+	}
+	statusReason, _ := status["reason"].(string)
+	switch status["status"] {
+	case "archived":
+		markStale(result, "project_archived_on_pypi", riskArchived, statusReason, "")
+	case "deprecated":
+		markStale(result, "project_deprecated_on_pypi", riskProjectDeprecated, statusReason, "")
+	case "quarantined":
+		markStale(result, "project_quarantined_on_pypi", riskQuarantined, statusReason, "")
+	}
+
+	// A release is yanked when all of its files are.
+	releases, _ := data["releases"].(map[string]interface{})
+	yanked, published := 0, 0
+	for _, files := range releases {
+		list, _ := files.([]interface{})
+		if len(list) == 0 {
+			continue
+		}
+		published++
+		all := true
+		for _, f := range list {
+			file, _ := f.(map[string]interface{})
+			all = all && file["yanked"] == true
+		}
+		if all {
+			yanked++
+		}
+	}
+	reason, _ := info["yanked_reason"].(string)
+	switch {
+	case published > 0 && yanked == published:
+		fmt.Printf("Info: Every release of %s is yanked on PyPI\n", result.Package)
+		markStale(result, "all_releases_yanked", riskAllYanked, reason, "")
+	case info["yanked"] == true:
+		fmt.Printf("Info: Latest release of %s is yanked on PyPI\n", result.Package)
+		markStale(result, "latest_release_yanked", riskLatestYanked, reason, "")
+	}
+
+	classifiers, _ := info["classifiers"].([]interface{})
+	for _, c := range classifiers {
+		if c == "Development Status :: 7 - Inactive" {
+			markStale(result, "inactive_development_status", riskInactive, "", "")
+		}
 	}
-	return results
 }
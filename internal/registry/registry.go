@@ -0,0 +1,162 @@
+package registry
+
+import (
+	"fmt"
+	"sync"
+)
+
+// DefaultConcurrency is the number of registry lookups run in parallel
+// unless SetConcurrency is called.
+const DefaultConcurrency = 10
+
+var (
+	concurrency    = DefaultConcurrency
+	unknownRetries = 0
+)
+
+// Status is the outcome of a registry lookup.
+type Status string
+
+const (
+	// StatusExists means the registry answered and the package is published.
+	StatusExists Status = "exists"
+	// StatusNotFound means the registry answered that the package does not exist.
+	StatusNotFound Status = "not_found"
+	// StatusReserved means the package does not exist but its namespace is
+	// claimed and the registry only lets the namespace's owners publish under
+	// it, so outsiders cannot claim the name.
+	StatusReserved Status = "reserved"
+	// StatusUnknown means the lookup failed (network error, timeout, 429, 5xx...)
+	// and nothing is known about the package. Error holds the reason.
+	StatusUnknown Status = "unknown"
+)
+
+// Severity ranks findings so that the most exploitable ones stand out,
+// independently of RiskScore which is capped at 100.
+type Severity string
+
+const (
+	// SeverityCritical marks a whole namespace (npm scope, Packagist vendor)
+	// being claimable: every package name under it can be registered.
+	SeverityCritical Severity = "critical"
+	// SeverityConfusion marks an internal package name (a workspace member
+	// or a package meant to come from a private registry) that is unclaimed
+	// on the public registry, where publishing it can shadow the real one.
+	SeverityConfusion Severity = "dependency_confusion"
+	// SeverityHigh marks a single claimable package name.
+	SeverityHigh Severity = "high"
+	// SeverityMedium marks a risky but not directly claimable package.
+	SeverityMedium Severity = "medium"
+)
+
+// PackageInfo is the result of checking a single package against a registry.
+type PackageInfo struct {
+	Status    Status
+	Error     string   `json:",omitempty"`
+	Retries   int      `json:",omitempty"`
+	Severity  Severity `json:",omitempty"`
+	Exists    bool
+	RiskScore int
+	Signals   []string
+	Metadata  map[string]interface{}
+	Package   string
+	// Versions lists the published versions, for checking declared version
+	// constraints. Analyze drops it from its results.
+	Versions []string `json:",omitempty"`
+	// Dependencies maps the packages the latest release depends on to their
+	// constraints, for resolving transitive dependencies. Analyze drops it
+	// from its results too.
+	Dependencies map[string]string `json:",omitempty"`
+}
+
+func newPackageInfo(packageName string) PackageInfo {
+	return PackageInfo{
+		Package:   packageName,
+		Status:    StatusUnknown,
+		Exists:    false,
+		RiskScore: 0,
+		Signals:   []string{},
+		Metadata:  make(map[string]interface{}),
+	}
+}
+
+// SetConcurrency sets how many registry lookups may run at once. Values
+// below one are treated as one.
+func SetConcurrency(n int) {
+	if n < 1 {
+		n = 1
+	}
+	concurrency = n
+}
+
+// SetUnknownRetries sets how many extra rounds of lookups are made for
+// packages whose status is still unknown after the first pass.
+func SetUnknownRetries(n int) {
+	if n < 0 {
+		n = 0
+	}
+	unknownRetries = n
+}
+
+// LookupAll runs check for every package on a bounded pool of workers and
+// returns the results in the same order as packages.
+func LookupAll(packages []string, check func(string) PackageInfo) []PackageInfo {
+	results := make([]PackageInfo, len(packages))
+
+	workers := concurrency
+	if workers > len(packages) {
+		workers = len(packages)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = check(packages[i])
+			}
+		}()
+	}
+
+	for i := range packages {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// AnalyzeDependencyRisks checks every package and collects the results by name.
+func AnalyzeDependencyRisks(packages []string, check func(string) PackageInfo) map[string]PackageInfo {
+	fmt.Printf("Analyzing %d packages (%d concurrent)...\n", len(packages), min(concurrency, len(packages)))
+
+	infos := LookupAll(packages, check)
+
+	for round := 1; round <= unknownRetries; round++ {
+		var retry []string
+		var retryIdx []int
+		for i, info := range infos {
+			if info.Status == StatusUnknown {
+				retry = append(retry, packages[i])
+				retryIdx = append(retryIdx, i)
+			}
+		}
+		if len(retry) == 0 {
+			break
+		}
+
+		fmt.Printf("Retrying %d unknown lookups (round %d/%d)...\n", len(retry), round, unknownRetries)
+		for j, info := range LookupAll(retry, check) {
+			infos[retryIdx[j]] = info
+		}
+	}
+
+	results := make(map[string]PackageInfo)
+	for i, info := range infos {
+		results[packages[i]] = info
+	}
+	return results
+}
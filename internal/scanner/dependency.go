@@ -0,0 +1,71 @@
+package scanner
+
+import "sort"
+
+// Kinds of dependency sources, as recorded in Dependency.Kind.
+const (
+	// SpecRegistry is a version, range or dist-tag served by the registry.
+	SpecRegistry = "registry"
+	// SpecAlias installs another registry package under a different name
+	// ("npm:real-name@1").
+	SpecAlias = "alias"
+	// SpecGit is a git URL or a hosted git shorthand ("user/repo",
+	// "github:user/repo").
+	SpecGit = "git"
+	// SpecVCS is a Mercurial, Subversion or Bazaar URL.
+	SpecVCS = "vcs"
+	// SpecTarball is a tarball downloaded from an arbitrary URL.
+	SpecTarball = "tarball"
+	// SpecFile is a local directory, tarball or symlink ("file:", "link:").
+	SpecFile = "file"
+	// SpecWorkspace is a package from the same workspace ("workspace:*").
+	SpecWorkspace = "workspace"
+)
+
+// Dependency is a single package reference found in a manifest file.
+type Dependency struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Type    string `json:"type,omitempty"`
+	// Extras and Marker are the PEP 508 extras and environment marker of a
+	// Python requirement.
+	Extras []string `json:"extras,omitempty"`
+	Marker string   `json:"marker,omitempty"`
+	// File is the included file a requirement was read from, when it is
+	// not the manifest itself (pip -r and -c includes).
+	File string `json:"file,omitempty"`
+	// Kind is how the dependency is installed (SpecRegistry, SpecGit...),
+	// set by manifests whose version specs can point outside the registry.
+	// An empty Kind means the registry.
+	Kind string `json:"kind,omitempty"`
+	// Canonical is the normalized registry name, or the real package an
+	// alias installs, set only when it differs from Name as spelled in the
+	// manifest.
+	Canonical string `json:"canonical,omitempty"`
+	// Resolved and Integrity are the download location and hash recorded
+	// by a lockfile. Resolved also holds the URL of git and tarball specs.
+	Resolved  string `json:"resolved,omitempty"`
+	Integrity string `json:"integrity,omitempty"`
+	// Source is the package index or repository a lockfile entry was
+	// resolved against, when the lockfile records it.
+	Source string `json:"source,omitempty"`
+	// Relation is RelationDirect or RelationTransitive for lockfile entries.
+	Relation string `json:"relation,omitempty"`
+}
+
+// FromRegistry reports whether the dependency is installed from the package
+// registry, and so should be looked up there.
+func (d Dependency) FromRegistry() bool {
+	return d.Kind == "" || d.Kind == SpecRegistry || d.Kind == SpecAlias
+}
+
+// sortDependencies orders dependencies by type and name so that manifests
+// decoded from JSON objects produce stable output.
+func sortDependencies(deps []Dependency) {
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].Type != deps[j].Type {
+			return deps[i].Type < deps[j].Type
+		}
+		return deps[i].Name < deps[j].Name
+	})
+}
@@ -9,12 +9,44 @@ import (
 )
 
 type PackageJSON struct {
-	Dependencies    map[string]string `json:"dependencies"`
-	DevDependencies map[string]string `json:"devDependencies"`
+	Name                 string            `json:"name"`
+	Workspaces           json.RawMessage   `json:"workspaces"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	// BundleDependencies is a list of names or true for "all dependencies";
+	// npm also accepts the bundledDependencies spelling.
+	BundleDependencies  json.RawMessage   `json:"bundleDependencies"`
+	BundledDependencies json.RawMessage   `json:"bundledDependencies"`
+	Overrides           json.RawMessage   `json:"overrides"`
+	Resolutions         map[string]string `json:"resolutions"`
+	PNPM                struct {
+		Overrides map[string]string `json:"overrides"`
+	} `json:"pnpm"`
 }
 
-func FindPackageJSONs(repoPath string) []string {
-	var packageJSONs []string
+// DirectNames returns every package name the manifest depends on directly.
+func (pkg PackageJSON) DirectNames() map[string]bool {
+	names := make(map[string]bool)
+	for _, m := range []map[string]string{pkg.Dependencies, pkg.DevDependencies, pkg.PeerDependencies, pkg.OptionalDependencies} {
+		for name := range m {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// FindNPMManifests returns every package.json and npm, Yarn or pnpm
+// lockfile under repoPath.
+func FindNPMManifests(repoPath string) []string {
+	return findNPMFiles(repoPath, func(name string) bool {
+		return name == "package.json" || npmLockfileNames[name]
+	})
+}
+
+func findNPMFiles(repoPath string, match func(name string) bool) []string {
+	var files []string
 
 	filepath.Walk(repoPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -26,18 +58,26 @@ func FindPackageJSONs(repoPath string) []string {
 			return filepath.SkipDir
 		}
 
-		if info.Name() == "package.json" {
-			packageJSONs = append(packageJSONs, path)
-			fmt.Printf("Found package.json: %s\n", path)
+		if !info.IsDir() && match(info.Name()) {
+			files = append(files, path)
+			fmt.Printf("Found %s: %s\n", info.Name(), path)
 		}
 
 		return nil
 	})
 
-	return packageJSONs
+	return files
+}
+
+// ExtractNPMManifest parses a package.json or a lockfile.
+func ExtractNPMManifest(path string) ([]Dependency, error) {
+	if IsNPMLockfile(path) {
+		return ExtractNPMLockfile(path)
+	}
+	return ExtractNPMDependencies(path)
 }
 
-func ExtractNPMDependencies(packageJSONPath string) (map[string]string, error) {
+func ExtractNPMDependencies(packageJSONPath string) ([]Dependency, error) {
 	data, err := os.ReadFile(packageJSONPath)
 	if err != nil {
 		return nil, err
@@ -48,57 +88,125 @@ func ExtractNPMDependencies(packageJSONPath string) (map[string]string, error) {
 		return nil, err
 	}
 
-	allDeps := make(map[string]string)
-
-	// Add production dependencies
-	for name, version := range pkg.Dependencies {
-		// Skip scoped packages
-		if !strings.HasPrefix(name, "@") {
-			allDeps[name] = version
+	var allDeps []Dependency
+	add := func(depType string, deps map[string]string) {
+		for name, version := range deps {
+			spec := ParseNPMSpec(name, version)
+			dep := Dependency{Name: name, Version: version, Type: depType, Kind: spec.Kind, Resolved: spec.URL}
+			if spec.Name != "" && spec.Name != name {
+				dep.Canonical = spec.Name
+			}
+			allDeps = append(allDeps, dep)
 		}
 	}
 
-	// Add dev dependencies
-	for name, version := range pkg.DevDependencies {
-		// Skip scoped packages
-		if !strings.HasPrefix(name, "@") {
-			allDeps[name] = version
-		}
-	}
+	add("dependencies", pkg.Dependencies)
+	add("devDependencies", pkg.DevDependencies)
+	add("peerDependencies", pkg.PeerDependencies)
+	add("optionalDependencies", pkg.OptionalDependencies)
+	add("bundleDependencies", bundledDeps(pkg))
+	add("overrides", npmOverrides(pkg.Overrides))
+	add("resolutions", overrideTargets(pkg.Resolutions, "/"))
+	add("pnpm.overrides", overrideTargets(pkg.PNPM.Overrides, ">"))
+
+	sortDependencies(allDeps)
 
 	fmt.Printf("Parsed %s: %d total dependencies\n", packageJSONPath, len(allDeps))
 	return allDeps, nil
 }
 
-func ExtractAllNPMDependencies(repoPath string) map[string]map[string]string {
-	depsByFile := make(map[string]map[string]string)
+// bundledDeps resolves bundleDependencies (a name list, or true for every
+// runtime dependency) to names and versions.
+func bundledDeps(pkg PackageJSON) map[string]string {
+	raw := pkg.BundleDependencies
+	if len(raw) == 0 {
+		raw = pkg.BundledDependencies
+	}
 
-	packageJSONs := FindPackageJSONs(repoPath)
+	deps := make(map[string]string)
+	var all bool
+	var names []string
+	switch {
+	case json.Unmarshal(raw, &all) == nil && all:
+		for name, version := range pkg.Dependencies {
+			deps[name] = version
+		}
+	case json.Unmarshal(raw, &names) == nil:
+		for _, name := range names {
+			deps[name] = pkg.Dependencies[name]
+		}
+	}
+	return deps
+}
 
-	for _, packageJSONPath := range packageJSONs {
-		deps, err := ExtractNPMDependencies(packageJSONPath)
-		if err == nil && len(deps) > 0 {
-			depsByFile[packageJSONPath] = deps
+// npmOverrides flattens npm's nested "overrides" object into the package
+// names it forces and the versions they are forced to. Keys may carry a
+// version selector ("foo@1.x") and nested objects override children, with
+// "." standing for the parent itself.
+func npmOverrides(raw json.RawMessage) map[string]string {
+	deps := make(map[string]string)
+
+	var walk func(raw json.RawMessage)
+	walk = func(raw json.RawMessage) {
+		var entries map[string]json.RawMessage
+		if json.Unmarshal(raw, &entries) != nil {
+			return
+		}
+		for key, value := range entries {
+			if key == "." {
+				continue
+			}
+			name := stripVersionSelector(key)
+
+			var version string
+			if json.Unmarshal(value, &version) == nil {
+				deps[name] = version
+				continue
+			}
+
+			var nested map[string]json.RawMessage
+			if json.Unmarshal(value, &nested) == nil {
+				var self string
+				json.Unmarshal(nested["."], &self)
+				deps[name] = self
+				walk(value)
+			}
 		}
 	}
+	walk(raw)
 
-	return depsByFile
+	return deps
 }
 
-func GetAllUniqueNPMDeps(repoPath string) []string {
-	depsByFile := ExtractAllNPMDependencies(repoPath)
+// overrideTargets maps Yarn resolutions ("**/a/b" style paths) and pnpm
+// overrides ("a>b" selectors) to the package each entry forces, which is
+// the last element of the key.
+func overrideTargets(entries map[string]string, sep string) map[string]string {
+	deps := make(map[string]string)
+	for key, version := range entries {
+		if version == "-" {
+			// pnpm uses "-" to remove a dependency entirely.
+			continue
+		}
 
-	uniqueMap := make(map[string]bool)
-	for _, deps := range depsByFile {
-		for name := range deps {
-			uniqueMap[name] = true
+		parts := strings.Split(key, sep)
+		last := parts[len(parts)-1]
+		// Yarn paths split scoped names; glue "@scope" back to its package.
+		if sep == "/" && len(parts) > 1 && strings.HasPrefix(parts[len(parts)-2], "@") {
+			last = parts[len(parts)-2] + "/" + last
+		}
+		if name := stripVersionSelector(last); name != "" && name != "**" {
+			deps[name] = version
 		}
 	}
+	return deps
+}
 
-	var uniqueDeps []string
-	for name := range uniqueMap {
-		uniqueDeps = append(uniqueDeps, name)
+// stripVersionSelector turns "foo@1.x" or "@scope/foo@^2" into the bare
+// package name.
+func stripVersionSelector(key string) string {
+	if at := strings.Index(key[min(1, len(key)):], "@") + 1; at > 0 {
+		return key[:at]
 	}
-
-	return uniqueDeps
+	return key
 }
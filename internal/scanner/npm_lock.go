@@ -0,0 +1,459 @@
+package scanner
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// RelationDirect marks a lockfile package that the project depends on by name.
+	RelationDirect = "direct"
+	// RelationTransitive marks a lockfile package pulled in by another package.
+	RelationTransitive = "transitive"
+)
+
+// npmLockfileNames are the lockfiles understood by ExtractNPMLockfile.
+var npmLockfileNames = map[string]bool{
+	"package-lock.json":   true,
+	"npm-shrinkwrap.json": true,
+	"yarn.lock":           true,
+	"pnpm-lock.yaml":      true,
+}
+
+// IsNPMLockfile reports whether path is an npm, Yarn or pnpm lockfile.
+func IsNPMLockfile(path string) bool {
+	return npmLockfileNames[filepath.Base(path)]
+}
+
+// ExtractNPMLockfile returns every package resolved in a lockfile. Packages
+// listed in the package.json next to the lockfile are marked direct, all
+// others transitive.
+func ExtractNPMLockfile(lockPath string) ([]Dependency, error) {
+	var deps []Dependency
+	var err error
+
+	switch filepath.Base(lockPath) {
+	case "package-lock.json", "npm-shrinkwrap.json":
+		deps, err = parsePackageLock(lockPath)
+	case "yarn.lock":
+		deps, err = parseYarnLock(lockPath)
+	case "pnpm-lock.yaml":
+		deps, err = parsePNPMLock(lockPath)
+	default:
+		return nil, fmt.Errorf("unsupported lockfile: %s", lockPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sortDependencies(deps)
+	fmt.Printf("Parsed %s: %d resolved packages\n", lockPath, len(deps))
+	return deps, nil
+}
+
+// siblingDirectDeps returns the dependency names declared in the
+// package.json that sits next to a lockfile.
+func siblingDirectDeps(lockPath string) map[string]bool {
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(lockPath), "package.json"))
+	if err != nil {
+		return make(map[string]bool)
+	}
+
+	var pkg PackageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return make(map[string]bool)
+	}
+	return pkg.DirectNames()
+}
+
+func relation(direct bool) string {
+	if direct {
+		return RelationDirect
+	}
+	return RelationTransitive
+}
+
+// lockDependencyType maps lockfile dev/optional flags to the package.json
+// section the package would come from.
+func lockDependencyType(dev, optional bool) string {
+	switch {
+	case dev:
+		return "devDependencies"
+	case optional:
+		return "optionalDependencies"
+	default:
+		return "dependencies"
+	}
+}
+
+// isRegistryResolution reports whether a resolved location points at a
+// package registry rather than a local path or a git host.
+func isRegistryResolution(resolved string) bool {
+	if resolved == "" {
+		return true
+	}
+	if !strings.HasPrefix(resolved, "https://") && !strings.HasPrefix(resolved, "http://") {
+		return false
+	}
+	for _, host := range []string{"github.com/", "codeload.github.com/", "gitlab.com/", "bitbucket.org/"} {
+		if strings.Contains(resolved, "://"+host) {
+			return false
+		}
+	}
+	return true
+}
+
+type packageLock struct {
+	LockfileVersion int                         `json:"lockfileVersion"`
+	Packages        map[string]packageLockEntry `json:"packages"`
+	Dependencies    map[string]packageLockEntry `json:"dependencies"`
+}
+
+type packageLockEntry struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Resolved    string `json:"resolved"`
+	Integrity   string `json:"integrity"`
+	Link        bool   `json:"link"`
+	Dev         bool   `json:"dev"`
+	Optional    bool   `json:"optional"`
+	DevOptional bool   `json:"devOptional"`
+	// Dependencies is a name->range map in v2+ "packages" entries but a
+	// name->entry map in v1 "dependencies" entries.
+	Dependencies         json.RawMessage   `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+}
+
+func parsePackageLock(lockPath string) ([]Dependency, error) {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var lock packageLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	var deps []Dependency
+
+	// Lockfile v2 and v3 list every installed package under "packages",
+	// keyed by its node_modules path.
+	if len(lock.Packages) > 0 {
+		direct := make(map[string]bool)
+		if root, ok := lock.Packages[""]; ok {
+			var rootDeps map[string]string
+			json.Unmarshal(root.Dependencies, &rootDeps)
+			for _, m := range []map[string]string{rootDeps, root.DevDependencies, root.OptionalDependencies, root.PeerDependencies} {
+				for name := range m {
+					direct[name] = true
+				}
+			}
+		} else {
+			direct = siblingDirectDeps(lockPath)
+		}
+
+		for path, entry := range lock.Packages {
+			idx := strings.LastIndex(path, "node_modules/")
+			if idx < 0 || entry.Link || !isRegistryResolution(entry.Resolved) {
+				continue
+			}
+
+			installed := path[idx+len("node_modules/"):]
+			name := installed
+			if entry.Name != "" {
+				// Aliased installs ("alias": "npm:real@1") record the real name.
+				name = entry.Name
+			}
+			topLevel := path == "node_modules/"+installed
+
+			deps = append(deps, Dependency{
+				Name:      name,
+				Version:   entry.Version,
+				Type:      lockDependencyType(entry.Dev || entry.DevOptional, entry.Optional),
+				Resolved:  entry.Resolved,
+				Integrity: entry.Integrity,
+				Relation:  relation(topLevel && direct[installed]),
+			})
+		}
+		return deps, nil
+	}
+
+	// Lockfile v1 nests dependencies of dependencies.
+	direct := siblingDirectDeps(lockPath)
+	var walk func(entries map[string]packageLockEntry, topLevel bool)
+	walk = func(entries map[string]packageLockEntry, topLevel bool) {
+		for name, entry := range entries {
+			if isRegistryResolution(entry.Resolved) && !strings.HasPrefix(entry.Version, "file:") {
+				deps = append(deps, Dependency{
+					Name:      name,
+					Version:   entry.Version,
+					Type:      lockDependencyType(entry.Dev, entry.Optional),
+					Resolved:  entry.Resolved,
+					Integrity: entry.Integrity,
+					Relation:  relation(topLevel && direct[name]),
+				})
+			}
+
+			var nested map[string]packageLockEntry
+			if json.Unmarshal(entry.Dependencies, &nested) == nil {
+				walk(nested, false)
+			}
+		}
+	}
+	walk(lock.Dependencies, true)
+
+	return deps, nil
+}
+
+// yarnDescriptorName returns the package name of a Yarn descriptor such as
+// "lodash@^4.17.0", "@babel/core@npm:^7.0.0" or "foo@workspace:packages/foo",
+// along with its protocol ("" for classic semver ranges). For npm aliases
+// ("foo@npm:bar@^1") the aliased package name is returned.
+func yarnDescriptorName(descriptor string) (name, protocol string) {
+	descriptor = strings.Trim(strings.TrimSpace(descriptor), `"`)
+
+	// Package names never contain "@" except as the scope marker, so the
+	// first "@" after the first character separates name and range.
+	at := strings.Index(descriptor[min(1, len(descriptor)):], "@") + 1
+	if at <= 0 {
+		return descriptor, ""
+	}
+	name, rng := descriptor[:at], descriptor[at+1:]
+
+	p, rest, found := strings.Cut(rng, ":")
+	if !found || strings.Contains(p, "/") {
+		return name, ""
+	}
+	if p == "npm" {
+		if aliased, _ := yarnDescriptorName(rest); aliased != rest {
+			name = aliased
+		}
+	}
+	return name, p
+}
+
+func parseYarnLock(lockPath string) ([]Dependency, error) {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	direct := siblingDirectDeps(lockPath)
+
+	if strings.Contains(string(data), "\n__metadata:") || strings.HasPrefix(string(data), "__metadata:") {
+		return parseYarnBerryLock(data, direct)
+	}
+	return parseYarnClassicLock(data, direct), nil
+}
+
+// parseYarnClassicLock handles the Yarn v1 lockfile format, which looks like
+// YAML but is not: top-level entries are comma-separated descriptor lists
+// and fields are "key value" pairs.
+func parseYarnClassicLock(data []byte, direct map[string]bool) []Dependency {
+	var deps []Dependency
+	var current *Dependency
+
+	flush := func() {
+		if current != nil && isRegistryResolution(current.Resolved) {
+			deps = append(deps, *current)
+		}
+		current = nil
+	}
+
+	sc := bufio.NewScanner(strings.NewReader(string(data)))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if !strings.HasPrefix(line, " ") {
+			flush()
+			descriptors := strings.Split(strings.TrimSuffix(trimmed, ":"), ",")
+			name, protocol := yarnDescriptorName(descriptors[0])
+			if protocol != "" && protocol != "npm" {
+				continue
+			}
+			current = &Dependency{Name: name, Type: "dependencies", Relation: relation(direct[name])}
+			continue
+		}
+
+		// Only the entry's own fields (two-space indent) matter; deeper lines
+		// list the entry's dependencies.
+		if current == nil || strings.HasPrefix(line, "    ") {
+			continue
+		}
+		key, value, _ := strings.Cut(trimmed, " ")
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		switch key {
+		case "version":
+			current.Version = value
+		case "resolved":
+			current.Resolved = value
+		case "integrity":
+			current.Integrity = value
+		}
+	}
+	flush()
+
+	return deps
+}
+
+type yarnBerryEntry struct {
+	Version    string `yaml:"version"`
+	Resolution string `yaml:"resolution"`
+	Checksum   string `yaml:"checksum"`
+	LinkType   string `yaml:"linkType"`
+}
+
+func parseYarnBerryLock(data []byte, direct map[string]bool) ([]Dependency, error) {
+	var lock map[string]yarnBerryEntry
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	var deps []Dependency
+	for key, entry := range lock {
+		if key == "__metadata" || entry.LinkType == "soft" {
+			continue
+		}
+
+		name, protocol := yarnDescriptorName(strings.Split(entry.Resolution, ",")[0])
+		if protocol != "npm" {
+			continue
+		}
+
+		deps = append(deps, Dependency{
+			Name:      name,
+			Version:   entry.Version,
+			Type:      "dependencies",
+			Resolved:  entry.Resolution,
+			Integrity: entry.Checksum,
+			Relation:  relation(direct[name]),
+		})
+	}
+	return deps, nil
+}
+
+type pnpmLock struct {
+	Importers       map[string]pnpmImporter `yaml:"importers"`
+	Dependencies    map[string]yaml.Node    `yaml:"dependencies"`
+	DevDependencies map[string]yaml.Node    `yaml:"devDependencies"`
+	Packages        map[string]pnpmPackage  `yaml:"packages"`
+}
+
+type pnpmImporter struct {
+	Dependencies         map[string]yaml.Node `yaml:"dependencies"`
+	DevDependencies      map[string]yaml.Node `yaml:"devDependencies"`
+	OptionalDependencies map[string]yaml.Node `yaml:"optionalDependencies"`
+}
+
+type pnpmPackage struct {
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"`
+	Dev        bool   `yaml:"dev"`
+	Optional   bool   `yaml:"optional"`
+	Resolution struct {
+		Integrity string `yaml:"integrity"`
+		Tarball   string `yaml:"tarball"`
+		Repo      string `yaml:"repo"`
+		Directory string `yaml:"directory"`
+	} `yaml:"resolution"`
+}
+
+// pnpmPackageKey splits a pnpm "packages" key into name and version. Keys
+// look like "/lodash/4.17.21" (v5), "/lodash@4.17.21" (v6) or
+// "lodash@4.17.21" (v9), optionally followed by a peer suffix in parens or,
+// in v5, after an underscore.
+func pnpmPackageKey(key string) (name, version string) {
+	key = strings.TrimPrefix(key, "/")
+	if i := strings.Index(key, "("); i >= 0 {
+		key = key[:i]
+	}
+	scoped := strings.HasPrefix(key, "@")
+
+	// v6+: name@version, where the name holds no "@" past its scope marker.
+	if at := strings.Index(key[min(1, len(key)):], "@") + 1; at > 0 {
+		name = key[:at]
+		if slashes := strings.Count(name, "/"); (scoped && slashes == 1) || (!scoped && slashes == 0) {
+			return name, key[at+1:]
+		}
+	}
+
+	// v5: name/version_peers
+	parts := strings.SplitN(key, "/", 3)
+	switch {
+	case scoped && len(parts) == 3:
+		name, version = parts[0]+"/"+parts[1], parts[2]
+	case !scoped && len(parts) >= 2:
+		name, version = parts[0], strings.Join(parts[1:], "/")
+	default:
+		return key, ""
+	}
+	if i := strings.Index(version, "_"); i >= 0 {
+		version = version[:i]
+	}
+	return name, version
+}
+
+func parsePNPMLock(lockPath string) ([]Dependency, error) {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var lock pnpmLock
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	direct := make(map[string]bool)
+	addDirect := func(m map[string]yaml.Node) {
+		for name := range m {
+			direct[name] = true
+		}
+	}
+	addDirect(lock.Dependencies)
+	addDirect(lock.DevDependencies)
+	for _, importer := range lock.Importers {
+		addDirect(importer.Dependencies)
+		addDirect(importer.DevDependencies)
+		addDirect(importer.OptionalDependencies)
+	}
+
+	var deps []Dependency
+	for key, pkg := range lock.Packages {
+		if pkg.Resolution.Repo != "" || pkg.Resolution.Directory != "" || !isRegistryResolution(pkg.Resolution.Tarball) {
+			continue
+		}
+
+		name, version := pnpmPackageKey(key)
+		if pkg.Name != "" {
+			name = pkg.Name
+		}
+		if pkg.Version != "" {
+			version = pkg.Version
+		}
+
+		deps = append(deps, Dependency{
+			Name:      name,
+			Version:   version,
+			Type:      lockDependencyType(pkg.Dev, pkg.Optional),
+			Resolved:  pkg.Resolution.Tarball,
+			Integrity: pkg.Resolution.Integrity,
+			Relation:  relation(direct[name]),
+		})
+	}
+	return deps, nil
+}
@@ -0,0 +1,143 @@
+package scanner
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExtractNPMLockfile(t *testing.T) {
+	tests := []struct {
+		lockfile string
+		want     []Dependency
+	}{
+		{
+			// Nested dependencies are transitive; git and file: entries are
+			// not installed from the registry and are left out.
+			lockfile: "v1/package-lock.json",
+			want: []Dependency{
+				{Name: "lodash", Version: "4.17.21", Type: "dependencies", Resolved: "https://registry.npmjs.org/lodash/-/lodash-4.17.21.tgz", Integrity: "sha512-lodash", Relation: RelationDirect},
+				{Name: "mocha", Version: "10.2.0", Type: "devDependencies", Resolved: "https://registry.npmjs.org/mocha/-/mocha-10.2.0.tgz", Relation: RelationDirect},
+				{Name: "ms", Version: "2.1.3", Type: "devDependencies", Resolved: "https://registry.npmjs.org/ms/-/ms-2.1.3.tgz", Relation: RelationTransitive},
+			},
+		},
+		{
+			// Aliases record the real package; hoisted-under packages are
+			// transitive; git and linked workspace entries are left out.
+			lockfile: "v3/package-lock.json",
+			want: []Dependency{
+				{Name: "@babel/core", Version: "7.23.0", Type: "dependencies", Resolved: "https://registry.npmjs.org/@babel/core/-/core-7.23.0.tgz", Integrity: "sha512-babel", Relation: RelationDirect},
+				{Name: "lodash", Version: "4.17.21", Type: "dependencies", Resolved: "https://registry.npmjs.org/lodash/-/lodash-4.17.21.tgz", Relation: RelationDirect},
+				{Name: "semver", Version: "6.3.1", Type: "dependencies", Resolved: "https://registry.npmjs.org/semver/-/semver-6.3.1.tgz", Relation: RelationTransitive},
+				{Name: "typescript", Version: "5.2.2", Type: "devDependencies", Resolved: "https://registry.npmjs.org/typescript/-/typescript-5.2.2.tgz", Relation: RelationTransitive},
+				{Name: "fsevents", Version: "2.3.3", Type: "optionalDependencies", Resolved: "https://registry.npmjs.org/fsevents/-/fsevents-2.3.3.tgz", Relation: RelationDirect},
+			},
+		},
+		{
+			lockfile: "yarn-classic/yarn.lock",
+			want: []Dependency{
+				{Name: "@babel/core", Version: "7.23.0", Type: "dependencies", Resolved: "https://registry.yarnpkg.com/@babel/core/-/core-7.23.0.tgz#abc", Integrity: "sha512-babel", Relation: RelationDirect},
+				{Name: "lodash", Version: "4.17.21", Type: "dependencies", Resolved: "https://registry.yarnpkg.com/lodash/-/lodash-4.17.21.tgz#def", Integrity: "sha512-lodash", Relation: RelationDirect},
+				{Name: "semver", Version: "6.3.1", Type: "dependencies", Resolved: "https://registry.yarnpkg.com/semver/-/semver-6.3.1.tgz#123", Relation: RelationTransitive},
+			},
+		},
+		{
+			// Only npm: resolutions are kept: not the soft-linked workspace
+			// root nor the git dependency.
+			lockfile: "yarn-berry/yarn.lock",
+			want: []Dependency{
+				{Name: "lodash", Version: "4.17.21", Type: "dependencies", Resolved: "lodash@npm:4.17.21", Integrity: "abc123", Relation: RelationDirect},
+				{Name: "ms", Version: "2.1.3", Type: "dependencies", Resolved: "ms@npm:2.1.3", Integrity: "def456", Relation: RelationTransitive},
+			},
+		},
+		{
+			lockfile: "pnpm/pnpm-lock.yaml",
+			want: []Dependency{
+				{Name: "@babel/core", Version: "7.23.0", Type: "dependencies", Integrity: "sha512-babel", Relation: RelationDirect},
+				{Name: "lodash", Version: "4.17.21", Type: "dependencies", Integrity: "sha512-lodash", Relation: RelationDirect},
+				{Name: "semver", Version: "6.3.1", Type: "dependencies", Integrity: "sha512-semver", Relation: RelationTransitive},
+				{Name: "typescript", Version: "5.2.2", Type: "devDependencies", Integrity: "sha512-ts", Relation: RelationDirect},
+				{Name: "fsevents", Version: "2.3.3", Type: "optionalDependencies", Integrity: "sha512-fsevents", Relation: RelationTransitive},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lockfile, func(t *testing.T) {
+			deps, err := ExtractNPMLockfile(filepath.Join("testdata", "npmlock", tt.lockfile))
+			if err != nil {
+				t.Fatalf("ExtractNPMLockfile: %v", err)
+			}
+			if !reflect.DeepEqual(deps, tt.want) {
+				t.Errorf("got  %+v\nwant %+v", deps, tt.want)
+			}
+		})
+	}
+}
+
+func TestYarnDescriptorName(t *testing.T) {
+	tests := []struct {
+		descriptor, name, protocol string
+	}{
+		{"lodash@^4.17.0", "lodash", ""},
+		{`"@babel/core@^7.0.0"`, "@babel/core", ""},
+		{"@babel/core@npm:^7.0.0", "@babel/core", "npm"},
+		{"foo@npm:bar@^1.0.0", "bar", "npm"},
+		{"foo@npm:@scope/bar@^1.0.0", "@scope/bar", "npm"},
+		{"foo@workspace:packages/foo", "foo", "workspace"},
+		{"my-fork@github:someone/my-fork", "my-fork", "github"},
+		{"my-fork@https://github.com/someone/my-fork.git", "my-fork", "https"},
+		{"lodash", "lodash", ""},
+	}
+
+	for _, tt := range tests {
+		name, protocol := yarnDescriptorName(tt.descriptor)
+		if name != tt.name || protocol != tt.protocol {
+			t.Errorf("yarnDescriptorName(%q) = %q, %q, want %q, %q", tt.descriptor, name, protocol, tt.name, tt.protocol)
+		}
+	}
+}
+
+func TestPNPMPackageKey(t *testing.T) {
+	tests := []struct {
+		key, name, version string
+	}{
+		{"/lodash/4.17.21", "lodash", "4.17.21"},
+		{"/@babel/core/7.23.0", "@babel/core", "7.23.0"},
+		{"/react-dom/18.2.0_react@18.2.0", "react-dom", "18.2.0"},
+		{"/lodash@4.17.21", "lodash", "4.17.21"},
+		{"/@babel/core@7.23.0", "@babel/core", "7.23.0"},
+		{"/react-dom@18.2.0(react@18.2.0)", "react-dom", "18.2.0"},
+		{"lodash@4.17.21", "lodash", "4.17.21"},
+		{"@babel/core@7.23.0(supports-color@8.1.1)", "@babel/core", "7.23.0"},
+	}
+
+	for _, tt := range tests {
+		name, version := pnpmPackageKey(tt.key)
+		if name != tt.name || version != tt.version {
+			t.Errorf("pnpmPackageKey(%q) = %q, %q, want %q, %q", tt.key, name, version, tt.name, tt.version)
+		}
+	}
+}
+
+func TestIsRegistryResolution(t *testing.T) {
+	tests := []struct {
+		resolved string
+		want     bool
+	}{
+		{"", true},
+		{"https://registry.npmjs.org/lodash/-/lodash-4.17.21.tgz", true},
+		{"https://npm.corp.example/lodash/-/lodash-4.17.21.tgz", true},
+		{"https://codeload.github.com/someone/my-fork/tar.gz/0123456", false},
+		{"https://github.com/someone/my-fork/archive/main.tar.gz", false},
+		{"git+ssh://git@github.com/someone/my-fork.git#0123456", false},
+		{"file:../local-lib", false},
+		{"packages/workspace-pkg", false},
+	}
+
+	for _, tt := range tests {
+		if got := isRegistryResolution(tt.resolved); got != tt.want {
+			t.Errorf("isRegistryResolution(%q) = %v, want %v", tt.resolved, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package scanner
+
+import (
+	"regexp"
+	"strings"
+)
+
+// githubShorthand matches npm's bare "user/repo[#ref]" GitHub shorthand.
+var githubShorthand = regexp.MustCompile(`^[^@%/\s.-][^:@%/\s]*/[^@\s/%]+(?:#.*)?$`)
+
+// gitHostPrefixes are the hosted git shorthands npm understands, with the
+// URL each one expands to.
+var gitHostPrefixes = map[string]string{
+	"github:":    "https://github.com/",
+	"gitlab:":    "https://gitlab.com/",
+	"bitbucket:": "https://bitbucket.org/",
+	"gist:":      "https://gist.github.com/",
+}
+
+// NPMSpec is a classified package.json dependency spec.
+type NPMSpec struct {
+	Kind string
+	// Name is the registry package to check for registry and alias specs.
+	Name string
+	// Range is the version range for registry and alias specs.
+	Range string
+	// URL is the location of git and tarball specs; hosted git shorthands
+	// are expanded to https URLs.
+	URL string
+}
+
+// ParseNPMSpec classifies the spec a dependency called name is declared
+// with, following the rules of npm's package argument parser.
+func ParseNPMSpec(name, spec string) NPMSpec {
+	spec = strings.TrimSpace(spec)
+
+	// Yarn patches wrap another spec: "patch:foo@npm%3A1.0.0#./fix.patch".
+	if rest, ok := strings.CutPrefix(spec, "patch:"); ok {
+		inner, _, _ := strings.Cut(rest, "#")
+		inner = strings.ReplaceAll(inner, "%3A", ":")
+		if at := strings.Index(inner[min(1, len(inner)):], "@") + 1; at > 0 {
+			return ParseNPMSpec(inner[:at], inner[at+1:])
+		}
+		if inner == "" {
+			inner = name
+		}
+		return NPMSpec{Kind: SpecRegistry, Name: inner}
+	}
+
+	switch {
+	case strings.HasPrefix(spec, "npm:"):
+		target := strings.TrimPrefix(spec, "npm:")
+		// Yarn also accepts a bare range after the protocol ("npm:^1.0.0").
+		if target == "" || strings.ContainsRune("0123456789^~<>=*", rune(target[0])) {
+			return NPMSpec{Kind: SpecRegistry, Name: name, Range: target}
+		}
+		targetName := stripVersionSelector(target)
+		return NPMSpec{Kind: SpecAlias, Name: targetName, Range: strings.TrimPrefix(target[len(targetName):], "@")}
+	case strings.HasPrefix(spec, "workspace:"):
+		return NPMSpec{Kind: SpecWorkspace, Name: name, Range: strings.TrimPrefix(spec, "workspace:")}
+	case strings.HasPrefix(spec, "file:"), strings.HasPrefix(spec, "link:"), strings.HasPrefix(spec, "portal:"),
+		strings.HasPrefix(spec, "./"), strings.HasPrefix(spec, "../"), strings.HasPrefix(spec, "~/"), strings.HasPrefix(spec, "/"):
+		return NPMSpec{Kind: SpecFile, URL: spec}
+	case strings.HasPrefix(spec, "git+"), strings.HasPrefix(spec, "git://"), strings.HasPrefix(spec, "git@"):
+		return NPMSpec{Kind: SpecGit, URL: spec}
+	case strings.HasPrefix(spec, "http://"), strings.HasPrefix(spec, "https://"):
+		if u, _, _ := strings.Cut(spec, "#"); strings.HasSuffix(u, ".git") {
+			return NPMSpec{Kind: SpecGit, URL: spec}
+		}
+		return NPMSpec{Kind: SpecTarball, URL: spec}
+	}
+
+	for prefix, base := range gitHostPrefixes {
+		if rest, ok := strings.CutPrefix(spec, prefix); ok {
+			return NPMSpec{Kind: SpecGit, URL: base + rest}
+		}
+	}
+	if githubShorthand.MatchString(spec) {
+		return NPMSpec{Kind: SpecGit, URL: "https://github.com/" + spec}
+	}
+
+	return NPMSpec{Kind: SpecRegistry, Name: name, Range: spec}
+}
@@ -0,0 +1,73 @@
+package scanner
+
+import "testing"
+
+func TestParseNPMSpec(t *testing.T) {
+	tests := []struct {
+		name, spec string
+		want       NPMSpec
+	}{
+		// Registry ranges and dist-tags.
+		{"lodash", "^4.17.0", NPMSpec{Kind: SpecRegistry, Name: "lodash", Range: "^4.17.0"}},
+		{"lodash", " ~1.2 ", NPMSpec{Kind: SpecRegistry, Name: "lodash", Range: "~1.2"}},
+		{"lodash", "latest", NPMSpec{Kind: SpecRegistry, Name: "lodash", Range: "latest"}},
+		{"@babel/core", ">=7 <8", NPMSpec{Kind: SpecRegistry, Name: "@babel/core", Range: ">=7 <8"}},
+		{"lodash", "", NPMSpec{Kind: SpecRegistry, Name: "lodash"}},
+		{"lodash", "   ", NPMSpec{Kind: SpecRegistry, Name: "lodash"}},
+
+		// npm: aliases, and Yarn's bare range after the protocol.
+		{"my-lodash", "npm:lodash@^4.0.0", NPMSpec{Kind: SpecAlias, Name: "lodash", Range: "^4.0.0"}},
+		{"babel", "npm:@babel/core@7.23.0", NPMSpec{Kind: SpecAlias, Name: "@babel/core", Range: "7.23.0"}},
+		{"foo", "npm:bar", NPMSpec{Kind: SpecAlias, Name: "bar"}},
+		{"lodash", "npm:^1.0.0", NPMSpec{Kind: SpecRegistry, Name: "lodash", Range: "^1.0.0"}},
+		{"lodash", "npm:", NPMSpec{Kind: SpecRegistry, Name: "lodash"}},
+
+		// Workspaces.
+		{"pkg", "workspace:*", NPMSpec{Kind: SpecWorkspace, Name: "pkg", Range: "*"}},
+		{"pkg", "workspace:^1.0.0", NPMSpec{Kind: SpecWorkspace, Name: "pkg", Range: "^1.0.0"}},
+
+		// Local paths.
+		{"lib", "file:../lib", NPMSpec{Kind: SpecFile, URL: "file:../lib"}},
+		{"lib", "link:../lib", NPMSpec{Kind: SpecFile, URL: "link:../lib"}},
+		{"lib", "portal:../lib", NPMSpec{Kind: SpecFile, URL: "portal:../lib"}},
+		{"lib", "./lib", NPMSpec{Kind: SpecFile, URL: "./lib"}},
+		{"lib", "../lib", NPMSpec{Kind: SpecFile, URL: "../lib"}},
+		{"lib", "~/lib", NPMSpec{Kind: SpecFile, URL: "~/lib"}},
+		{"lib", "/opt/lib", NPMSpec{Kind: SpecFile, URL: "/opt/lib"}},
+
+		// Git URLs and hosted shorthands.
+		{"b", "git+https://github.com/a/b.git#v1", NPMSpec{Kind: SpecGit, URL: "git+https://github.com/a/b.git#v1"}},
+		{"b", "git+ssh://git@github.com/a/b.git", NPMSpec{Kind: SpecGit, URL: "git+ssh://git@github.com/a/b.git"}},
+		{"b", "git://github.com/a/b.git", NPMSpec{Kind: SpecGit, URL: "git://github.com/a/b.git"}},
+		{"b", "git@github.com:a/b.git", NPMSpec{Kind: SpecGit, URL: "git@github.com:a/b.git"}},
+		{"b", "https://github.com/a/b.git#main", NPMSpec{Kind: SpecGit, URL: "https://github.com/a/b.git#main"}},
+		{"b", "github:a/b#main", NPMSpec{Kind: SpecGit, URL: "https://github.com/a/b#main"}},
+		{"b", "gitlab:a/b", NPMSpec{Kind: SpecGit, URL: "https://gitlab.com/a/b"}},
+		{"b", "bitbucket:a/b", NPMSpec{Kind: SpecGit, URL: "https://bitbucket.org/a/b"}},
+		{"b", "gist:abc123", NPMSpec{Kind: SpecGit, URL: "https://gist.github.com/abc123"}},
+		{"b", "a/b", NPMSpec{Kind: SpecGit, URL: "https://github.com/a/b"}},
+		{"b", "a/b#semver:^1.0", NPMSpec{Kind: SpecGit, URL: "https://github.com/a/b#semver:^1.0"}},
+
+		// Tarballs.
+		{"b", "https://example.com/b-1.0.0.tgz", NPMSpec{Kind: SpecTarball, URL: "https://example.com/b-1.0.0.tgz"}},
+		{"b", "http://example.com/b.tar.gz#sha", NPMSpec{Kind: SpecTarball, URL: "http://example.com/b.tar.gz#sha"}},
+
+		// Yarn patches classify the spec they wrap.
+		{"foo", "patch:foo@npm%3A1.0.0#./fix.patch", NPMSpec{Kind: SpecRegistry, Name: "foo", Range: "1.0.0"}},
+		{"@s/p", "patch:@s/p@npm%3A2.0.0#./p.patch", NPMSpec{Kind: SpecRegistry, Name: "@s/p", Range: "2.0.0"}},
+		{"alias", "patch:alias@npm%3Areal@1.0.0#./a.patch", NPMSpec{Kind: SpecAlias, Name: "real", Range: "1.0.0"}},
+		{"foo", "patch:foo", NPMSpec{Kind: SpecRegistry, Name: "foo"}},
+		{"foo", "patch:", NPMSpec{Kind: SpecRegistry, Name: "foo"}},
+
+		// Not GitHub shorthands.
+		{"x", "@scope/pkg", NPMSpec{Kind: SpecRegistry, Name: "x", Range: "@scope/pkg"}},
+		{"x", ".hidden/pkg", NPMSpec{Kind: SpecRegistry, Name: "x", Range: ".hidden/pkg"}},
+		{"x", "a/b/c", NPMSpec{Kind: SpecRegistry, Name: "x", Range: "a/b/c"}},
+	}
+
+	for _, tt := range tests {
+		if got := ParseNPMSpec(tt.name, tt.spec); got != tt.want {
+			t.Errorf("ParseNPMSpec(%q, %q) = %+v, want %+v", tt.name, tt.spec, got, tt.want)
+		}
+	}
+}
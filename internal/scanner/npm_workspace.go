@@ -0,0 +1,186 @@
+package scanner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FindNPMWorkspacePackages returns the packages that belong to an npm, Yarn,
+// pnpm, Lerna or Nx workspace under repoPath, keyed by package name, with the
+// directory each one lives in. Package managers resolve these names locally,
+// so they are internal even when they look like registry dependencies.
+func FindNPMWorkspacePackages(repoPath string) map[string]string {
+	// Package names by directory, and the directories of Nx projects.
+	names := make(map[string]string)
+	var nxProjects []string
+	filepath.Walk(repoPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() && path != repoPath && (info.Name() == "node_modules" || strings.HasPrefix(info.Name(), ".")) {
+			return filepath.SkipDir
+		}
+
+		switch info.Name() {
+		case "package.json":
+			if pkg, err := readPackageJSON(path); err == nil && pkg.Name != "" {
+				names[filepath.Dir(path)] = pkg.Name
+			}
+		case "project.json":
+			nxProjects = append(nxProjects, filepath.Dir(path))
+		}
+		return nil
+	})
+
+	internal := make(map[string]string)
+	for dir := range names {
+		patterns, isRoot := workspacePatterns(dir)
+		if !isRoot {
+			continue
+		}
+		internal[names[dir]] = dir
+
+		var matchers []*regexp.Regexp
+		for _, p := range patterns {
+			// Negated patterns only exclude directories; including a few
+			// extra names is harmless.
+			if !strings.HasPrefix(p, "!") {
+				matchers = append(matchers, workspaceGlob(p))
+			}
+		}
+
+		for member, name := range names {
+			rel, err := filepath.Rel(dir, member)
+			if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+				continue
+			}
+			rel = filepath.ToSlash(rel)
+			for _, m := range matchers {
+				if m.MatchString(rel) {
+					internal[name] = member
+					break
+				}
+			}
+		}
+	}
+
+	// Nx projects are declared one project.json per project rather than
+	// through globs.
+	for _, dir := range nxProjects {
+		if name, ok := names[dir]; ok {
+			internal[name] = dir
+		}
+	}
+
+	return internal
+}
+
+// workspacePatterns returns the member globs declared by the workspace
+// configuration files in dir, and whether dir is a workspace root at all.
+func workspacePatterns(dir string) ([]string, bool) {
+	var patterns []string
+	isRoot := false
+
+	if pkg, err := readPackageJSON(filepath.Join(dir, "package.json")); err == nil && len(pkg.Workspaces) > 0 {
+		// "workspaces" is a list of globs, or {"packages": [...]} in Yarn.
+		var list []string
+		if json.Unmarshal(pkg.Workspaces, &list) != nil {
+			var obj struct {
+				Packages []string `json:"packages"`
+			}
+			json.Unmarshal(pkg.Workspaces, &obj)
+			list = obj.Packages
+		}
+		patterns = append(patterns, list...)
+		isRoot = true
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "pnpm-workspace.yaml")); err == nil {
+		var ws struct {
+			Packages []string `yaml:"packages"`
+		}
+		yaml.Unmarshal(data, &ws)
+		patterns = append(patterns, ws.Packages...)
+		isRoot = true
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "lerna.json")); err == nil {
+		var lerna struct {
+			Packages []string `json:"packages"`
+		}
+		json.Unmarshal(data, &lerna)
+		if len(lerna.Packages) == 0 {
+			lerna.Packages = []string{"packages/*"}
+		}
+		patterns = append(patterns, lerna.Packages...)
+		isRoot = true
+	}
+
+	// Older Nx and Angular workspaces list project roots in workspace.json,
+	// either as paths or as objects with a "root" field.
+	if data, err := os.ReadFile(filepath.Join(dir, "workspace.json")); err == nil {
+		var ws struct {
+			Projects map[string]json.RawMessage `json:"projects"`
+		}
+		json.Unmarshal(data, &ws)
+		for _, raw := range ws.Projects {
+			var root string
+			if json.Unmarshal(raw, &root) != nil {
+				var project struct {
+					Root string `json:"root"`
+				}
+				json.Unmarshal(raw, &project)
+				root = project.Root
+			}
+			if root != "" {
+				patterns = append(patterns, root)
+			}
+		}
+		isRoot = true
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "nx.json")); err == nil {
+		isRoot = true
+	}
+
+	return patterns, isRoot
+}
+
+// workspaceGlob compiles a workspace glob ("packages/*", "apps/**") into a
+// regexp matching slash-separated paths relative to the workspace root.
+func workspaceGlob(pattern string) *regexp.Regexp {
+	pattern = strings.Trim(strings.TrimPrefix(pattern, "./"), "/")
+
+	var re strings.Builder
+	re.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**"):
+			re.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			re.WriteString("[^/]*")
+		case pattern[i] == '?':
+			re.WriteString("[^/]")
+		default:
+			re.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	re.WriteString("$")
+	return regexp.MustCompile(re.String())
+}
+
+func readPackageJSON(path string) (PackageJSON, error) {
+	var pkg PackageJSON
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return pkg, err
+	}
+	err = json.Unmarshal(data, &pkg)
+	return pkg, err
+}
@@ -9,11 +9,99 @@ import (
 )
 
 type ComposerJSON struct {
+	Name       string            `json:"name"`
 	Require    map[string]string `json:"require"`
 	RequireDev map[string]string `json:"require-dev"`
+	// Replace and Provide name packages this one stands in for; Conflict
+	// only constrains versions and Suggest maps names to descriptions.
+	Replace  composerLinks `json:"replace"`
+	Provide  composerLinks `json:"provide"`
+	Conflict composerLinks `json:"conflict"`
+	Suggest  composerLinks `json:"suggest"`
+	// Repositories is a list of repositories, or an object keyed by name.
+	Repositories json.RawMessage `json:"repositories"`
+	// MinimumStability is the lowest stability (dev, alpha, beta, RC)
+	// installed for requirements without a stability flag.
+	MinimumStability string `json:"minimum-stability"`
 }
 
-func FindComposerJSONs(repoPath string) []string {
+// composerLinks maps package names to constraints or descriptions. PHP's
+// JSON encoder writes an empty one as [], which decodes to an empty map.
+type composerLinks map[string]string
+
+func (l *composerLinks) UnmarshalJSON(data []byte) error {
+	var links map[string]string
+	if json.Unmarshal(data, &links) == nil {
+		*l = links
+	}
+	return nil
+}
+
+// ComposerRepository is an entry of composer.json's "repositories".
+type ComposerRepository struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+	// Package holds the inline definition(s) of a "package" repository.
+	Package json.RawMessage `json:"package"`
+}
+
+// composerPackageDefinition is a package defined inline by a "package"
+// repository.
+type composerPackageDefinition struct {
+	Name   string `json:"name"`
+	Source struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	} `json:"source"`
+	Dist struct {
+		URL string `json:"url"`
+	} `json:"dist"`
+}
+
+// packages decodes the inline package definitions of a "package"
+// repository, given as one object or a list of versions.
+func (r ComposerRepository) packages() []composerPackageDefinition {
+	var defs []composerPackageDefinition
+	if json.Unmarshal(r.Package, &defs) != nil {
+		var def composerPackageDefinition
+		if json.Unmarshal(r.Package, &def) == nil {
+			defs = append(defs, def)
+		}
+	}
+	return defs
+}
+
+// composerRepositories decodes "repositories" in either of its forms. Entries
+// that are not objects, such as {"packagist.org": false}, are skipped.
+func composerRepositories(raw json.RawMessage) []ComposerRepository {
+	var entries []json.RawMessage
+	if json.Unmarshal(raw, &entries) != nil {
+		var named map[string]json.RawMessage
+		json.Unmarshal(raw, &named)
+		for _, entry := range named {
+			entries = append(entries, entry)
+		}
+	}
+
+	var repos []ComposerRepository
+	for _, entry := range entries {
+		var repo ComposerRepository
+		if json.Unmarshal(entry, &repo) == nil && repo.Type != "" {
+			repos = append(repos, repo)
+		}
+	}
+	return repos
+}
+
+// FindComposerManifests returns every composer.json and composer.lock under
+// repoPath.
+func FindComposerManifests(repoPath string) []string {
+	return findComposerFiles(repoPath, func(name string) bool {
+		return name == "composer.json" || name == "composer.lock"
+	})
+}
+
+func findComposerFiles(repoPath string, match func(name string) bool) []string {
 	var composerFiles []string
 
 	filepath.Walk(repoPath, func(path string, info os.FileInfo, err error) error {
@@ -28,9 +116,9 @@ func FindComposerJSONs(repoPath string) []string {
 			}
 		}
 
-		if info.Name() == "composer.json" {
+		if !info.IsDir() && match(info.Name()) {
 			composerFiles = append(composerFiles, path)
-			fmt.Printf("Found composer.json: %s\n", path)
+			fmt.Printf("Found %s: %s\n", info.Name(), path)
 		}
 
 		return nil
@@ -39,74 +127,203 @@ func FindComposerJSONs(repoPath string) []string {
 	return composerFiles
 }
 
-func ExtractPHPDependencies(composerJSONPath string) map[string][]string {
-	result := map[string][]string{
-		"require":     {},
-		"require-dev": {},
+// ExtractPHPManifest parses a composer.json or a composer.lock.
+func ExtractPHPManifest(path string) ([]Dependency, error) {
+	if filepath.Base(path) == "composer.lock" {
+		return ExtractComposerLock(path)
 	}
+	return ExtractPHPDependencies(path)
+}
 
+// ExtractPHPDependencies returns the packages a composer.json requires and
+// suggests. Virtual packages (replaced or provided by this package, by a
+// locked package, or "*-implementation" names) are left out since no
+// repository serves them under that name. Packages served by a vcs or
+// package repository get that repository as their source: Composer falls
+// back to Packagist for them if it disappears. Conflicts are never
+// installed and are not listed.
+func ExtractPHPDependencies(composerJSONPath string) ([]Dependency, error) {
 	data, err := os.ReadFile(composerJSONPath)
 	if err != nil {
-		return result
+		return nil, err
 	}
 
 	var composer ComposerJSON
 	if err := json.Unmarshal(data, &composer); err != nil {
-		return result
+		return nil, err
 	}
 
-	// Process require dependencies
-	for pkgName := range composer.Require {
-		// Skip PHP core and extensions
-		if !strings.HasPrefix(pkgName, "php") && !strings.HasPrefix(pkgName, "ext-") && strings.Contains(pkgName, "/") {
-			result["require"] = append(result["require"], strings.ToLower(pkgName))
+	repos := composerRepositories(composer.Repositories)
+	virtual := composerVirtualPackages(composerJSONPath, composer)
+	sources := make(map[string]string)
+	var required []string
+	for _, section := range []map[string]string{composer.Require, composer.RequireDev, composer.Suggest} {
+		for pkgName := range section {
+			required = append(required, strings.ToLower(pkgName))
 		}
 	}
 
-	// Process require-dev dependencies
-	for pkgName := range composer.RequireDev {
-		// Skip PHP core and extensions
-		if !strings.HasPrefix(pkgName, "php") && !strings.HasPrefix(pkgName, "ext-") && strings.Contains(pkgName, "/") {
-			result["require-dev"] = append(result["require-dev"], strings.ToLower(pkgName))
+	var deps []Dependency
+	for _, repo := range repos {
+		switch repo.Type {
+		case "vcs", "git", "github":
+			// VCS repositories are checked for repo-jacking, and the
+			// package they serve for confusion with Packagist.
+			deps = append(deps, Dependency{Name: repo.URL, Type: "repositories", Kind: SpecGit, Resolved: repo.URL})
+			if name := vcsRepositoryPackage(repo.URL, required); name != "" {
+				sources[name] = repo.URL
+			}
+		case "package":
+			for _, def := range repo.packages() {
+				url := def.Dist.URL
+				if def.Source.URL != "" {
+					url = def.Source.URL
+				}
+				if def.Source.Type == "git" {
+					deps = append(deps, Dependency{Name: def.Source.URL, Type: "repositories", Kind: SpecGit, Resolved: def.Source.URL})
+				}
+				sources[strings.ToLower(def.Name)] = url
+			}
 		}
 	}
 
-	fmt.Printf("Parsed %s: %d require, %d require-dev\n", composerJSONPath, len(result["require"]), len(result["require-dev"]))
-	return result
-}
+	counts := make(map[string]int)
+	for section, packages := range map[string]map[string]string{"require": composer.Require, "require-dev": composer.RequireDev, "suggest": composer.Suggest} {
+		for pkgName, version := range packages {
+			name := strings.ToLower(pkgName)
+			// Names without a vendor cannot be published on Packagist,
+			// and "*-implementation" names are virtual by convention.
+			if isPlatformPackage(name) || virtual[name] || !strings.Contains(name, "/") || strings.HasSuffix(name, "-implementation") {
+				continue
+			}
+			if section == "suggest" {
+				// Suggest values are descriptions, not constraints.
+				version = ""
+			} else if composer.MinimumStability != "" && !strings.EqualFold(composer.MinimumStability, "stable") && version != "" && !strings.Contains(version, "@") {
+				// Carry minimum-stability as the stability flag it
+				// stands for, so the constraint check allows the same
+				// prereleases Composer would.
+				version += "@" + composer.MinimumStability
+			}
+			deps = append(deps, Dependency{Name: name, Version: version, Type: section, Source: sources[name]})
+			counts[section]++
+		}
+	}
 
-func ExtractAllPHPDependencies(repoPath string) map[string]map[string][]string {
-	depsByFile := make(map[string]map[string][]string)
+	sortDependencies(deps)
 
-	composerFiles := FindComposerJSONs(repoPath)
+	fmt.Printf("Parsed %s: %d require, %d require-dev, %d suggest\n", composerJSONPath, counts["require"], counts["require-dev"], counts["suggest"])
+	return deps, nil
+}
 
-	for _, composerFile := range composerFiles {
-		deps := ExtractPHPDependencies(composerFile)
-		if len(deps["require"]) > 0 || len(deps["require-dev"]) > 0 {
-			depsByFile[composerFile] = deps
+// composerVirtualPackages returns the names that a composer.json, or the
+// packages locked in the composer.lock beside it, replace or provide.
+func composerVirtualPackages(composerJSONPath string, composer ComposerJSON) map[string]bool {
+	virtual := make(map[string]bool)
+	add := func(names composerLinks) {
+		for name := range names {
+			virtual[strings.ToLower(name)] = true
 		}
 	}
+	add(composer.Replace)
+	add(composer.Provide)
 
-	return depsByFile
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(composerJSONPath), "composer.lock"))
+	if err == nil {
+		var lock composerLock
+		if json.Unmarshal(data, &lock) == nil {
+			for _, pkg := range append(lock.Packages, lock.PackagesDev...) {
+				add(pkg.Replace)
+				add(pkg.Provide)
+			}
+		}
+	}
+
+	return virtual
 }
 
-func GetAllUniquePHPDeps(repoPath string) []string {
-	depsByFile := ExtractAllPHPDependencies(repoPath)
+// vcsRepositoryPackage guesses which required package a VCS repository
+// serves from its URL: the package whose vendor/name matches owner/repo, or
+// else whose name matches the repository name. Composer reads the real name
+// from the repository's composer.json, which is not fetched.
+func vcsRepositoryPackage(repoURL string, required []string) string {
+	path := strings.TrimSuffix(strings.TrimSuffix(strings.ToLower(repoURL), "/"), ".git")
+	if i := strings.Index(path, "://"); i >= 0 {
+		path = path[i+3:]
+	}
+	path = strings.ReplaceAll(path, ":", "/")
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	ownerRepo := parts[len(parts)-2] + "/" + parts[len(parts)-1]
 
-	uniqueMap := make(map[string]bool)
-	for _, deps := range depsByFile {
-		for _, name := range deps["require"] {
-			uniqueMap[name] = true
+	match := ""
+	for _, name := range required {
+		if name == ownerRepo {
+			return name
 		}
-		for _, name := range deps["require-dev"] {
-			uniqueMap[name] = true
+		if _, pkg, _ := strings.Cut(name, "/"); pkg == parts[len(parts)-1] {
+			match = name
 		}
 	}
+	return match
+}
 
-	var uniqueDeps []string
-	for name := range uniqueMap {
-		uniqueDeps = append(uniqueDeps, name)
+// FindComposerPathPackages returns the packages served by "path"
+// repositories of the composer.json files under repoPath, keyed by package
+// name, with the directory each one lives in. Composer symlinks them from
+// the repository itself, so they are internal.
+func FindComposerPathPackages(repoPath string) map[string]string {
+	internal := make(map[string]string)
+	for _, path := range findConfigFiles(repoPath, func(path string) bool {
+		return filepath.Base(path) == "composer.json"
+	}) {
+		composer, err := readComposerJSON(path)
+		if err != nil {
+			continue
+		}
+		for _, repo := range composerRepositories(composer.Repositories) {
+			if repo.Type != "path" {
+				continue
+			}
+			dirs, _ := filepath.Glob(filepath.Join(filepath.Dir(path), repo.URL))
+			for _, dir := range dirs {
+				if member, err := readComposerJSON(filepath.Join(dir, "composer.json")); err == nil && member.Name != "" {
+					internal[strings.ToLower(member.Name)] = dir
+				}
+			}
+		}
+	}
+	return internal
+}
+
+func readComposerJSON(path string) (ComposerJSON, error) {
+	var composer ComposerJSON
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return composer, err
 	}
+	err = json.Unmarshal(data, &composer)
+	return composer, err
+}
+
+// composerPlatformPackages are the platform packages Composer provides
+// itself, besides the ext-* and lib-* ones.
+var composerPlatformPackages = map[string]bool{
+	"php":                  true,
+	"php-64bit":            true,
+	"php-ipv6":             true,
+	"php-zts":              true,
+	"php-debug":            true,
+	"hhvm":                 true,
+	"composer":             true,
+	"composer-plugin-api":  true,
+	"composer-runtime-api": true,
+}
 
-	return uniqueDeps
+// isPlatformPackage reports whether name refers to PHP itself, an extension,
+// a system library or Composer.
+func isPlatformPackage(name string) bool {
+	return composerPlatformPackages[name] || strings.HasPrefix(name, "ext-") || strings.HasPrefix(name, "lib-")
 }
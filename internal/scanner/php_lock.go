@@ -0,0 +1,85 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type composerLock struct {
+	Packages    []composerLockPackage `json:"packages"`
+	PackagesDev []composerLockPackage `json:"packages-dev"`
+}
+
+type composerLockPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Source  struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	} `json:"source"`
+	Dist struct {
+		Type   string `json:"type"`
+		URL    string `json:"url"`
+		Shasum string `json:"shasum"`
+	} `json:"dist"`
+	// NotificationURL points at the repository that served the package:
+	// https://packagist.org/downloads/ for Packagist, another host for
+	// private Packagist or Satis instances, empty for VCS repositories.
+	NotificationURL string        `json:"notification-url"`
+	Replace         composerLinks `json:"replace"`
+	Provide         composerLinks `json:"provide"`
+}
+
+// ExtractComposerLock returns every package pinned in a composer.lock along
+// with the repository it was installed from. Packages installed from a VCS
+// or package repository, which have no notification URL, are classified by
+// their source (SpecGit, SpecVCS or SpecTarball) so that they are not looked
+// up on Packagist.
+func ExtractComposerLock(lockPath string) ([]Dependency, error) {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var lock composerLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	var deps []Dependency
+	for section, packages := range map[string][]composerLockPackage{"require": lock.Packages, "require-dev": lock.PackagesDev} {
+		for _, pkg := range packages {
+			if pkg.Dist.Type == "path" {
+				continue
+			}
+
+			dep := Dependency{
+				Name:      strings.ToLower(pkg.Name),
+				Version:   pkg.Version,
+				Type:      section,
+				Resolved:  pkg.Dist.URL,
+				Integrity: pkg.Dist.Shasum,
+				Source:    strings.TrimSuffix(pkg.NotificationURL, "downloads/"),
+			}
+			if pkg.NotificationURL == "" {
+				switch {
+				case pkg.Source.Type == "git":
+					dep.Kind = SpecGit
+					dep.Resolved = pkg.Source.URL
+				case pkg.Source.Type != "":
+					dep.Kind = SpecVCS
+					dep.Resolved = pkg.Source.URL
+				case pkg.Dist.URL != "":
+					dep.Kind = SpecTarball
+				}
+			}
+			deps = append(deps, dep)
+		}
+	}
+
+	sortDependencies(deps)
+	fmt.Printf("Parsed %s: %d locked packages\n", lockPath, len(deps))
+	return deps, nil
+}
@@ -0,0 +1,97 @@
+package scanner
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExtractPHPDependencies(t *testing.T) {
+	deps, err := ExtractPHPDependencies(filepath.Join("testdata", "composer", "composer.json"))
+	if err != nil {
+		t.Fatalf("ExtractPHPDependencies: %v", err)
+	}
+
+	// Platform and virtual packages are left out, but not packages whose
+	// name merely starts with "php".
+	want := []Dependency{
+		{Name: "https://github.com/acme/forked-lib", Type: "repositories", Kind: SpecGit, Resolved: "https://github.com/acme/forked-lib"},
+		{Name: "acme/forked-lib", Version: "dev-main", Type: "require", Source: "https://github.com/acme/forked-lib"},
+		{Name: "acme/private-lib", Version: "^1.0", Type: "require"},
+		{Name: "monolog/monolog", Version: "^3.0", Type: "require"},
+		{Name: "phpstan/phpstan", Version: "^1.10", Type: "require-dev"},
+		{Name: "phpunit/phpunit", Version: "^10.0", Type: "require-dev"},
+	}
+	if !reflect.DeepEqual(deps, want) {
+		t.Errorf("got  %+v\nwant %+v", deps, want)
+	}
+}
+
+func TestExtractComposerLock(t *testing.T) {
+	deps, err := ExtractComposerLock(filepath.Join("testdata", "composer", "composer.lock"))
+	if err != nil {
+		t.Fatalf("ExtractComposerLock: %v", err)
+	}
+
+	// Packages without a notification URL come from a VCS or package
+	// repository, not Packagist; path repositories are left out.
+	want := []Dependency{
+		{Name: "acme/forked-lib", Version: "dev-main", Type: "require", Kind: SpecGit, Resolved: "https://github.com/acme/forked-lib.git"},
+		{Name: "acme/hg-lib", Version: "1.0.0", Type: "require", Kind: SpecVCS, Resolved: "https://hg.acme.example/hg-lib"},
+		{Name: "acme/inline-lib", Version: "1.0.0", Type: "require", Kind: SpecTarball, Resolved: "https://downloads.acme.example/inline-lib-1.0.0.zip"},
+		{Name: "acme/private-lib", Version: "1.2.0", Type: "require", Resolved: "https://repo.acme.example/dist/acme/private-lib/1.2.0.zip", Integrity: "abc", Source: "https://repo.acme.example/"},
+		{Name: "monolog/monolog", Version: "3.5.0", Type: "require", Resolved: "https://api.github.com/repos/Seldaek/monolog/zipball/c915e2634718dbc8a4a15c61b0e62e7a44e14448", Source: "https://packagist.org/"},
+		{Name: "phpunit/phpunit", Version: "10.5.0", Type: "require-dev", Resolved: "https://api.github.com/repos/sebastianbergmann/phpunit/zipball/abc", Source: "https://packagist.org/"},
+	}
+	if !reflect.DeepEqual(deps, want) {
+		t.Errorf("got  %+v\nwant %+v", deps, want)
+	}
+}
+
+func TestIsPlatformPackage(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"php", true},
+		{"php-64bit", true},
+		{"php-ipv6", true},
+		{"php-zts", true},
+		{"php-debug", true},
+		{"hhvm", true},
+		{"composer", true},
+		{"composer-plugin-api", true},
+		{"composer-runtime-api", true},
+		{"ext-json", true},
+		{"lib-curl", true},
+		{"phpunit/phpunit", false},
+		{"phpstan/phpstan", false},
+		{"phpspec/prophecy", false},
+		{"composer/semver", false},
+		{"php-http/client-common", false},
+	}
+
+	for _, tt := range tests {
+		if got := isPlatformPackage(tt.name); got != tt.want {
+			t.Errorf("isPlatformPackage(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExtractPHPDependenciesMinimumStability(t *testing.T) {
+	deps, err := ExtractPHPDependencies(filepath.Join("testdata", "composer", "minimum-stability", "composer.json"))
+	if err != nil {
+		t.Fatalf("ExtractPHPDependencies: %v", err)
+	}
+
+	// minimum-stability becomes the stability flag of requirements that
+	// have none.
+	want := []Dependency{
+		{Name: "acme/branch", Version: "dev-main@RC", Type: "require"},
+		{Name: "acme/flagged", Version: "^2.0@beta", Type: "require"},
+		{Name: "acme/ranged", Version: "^3.0@RC", Type: "require"},
+	}
+	if !reflect.DeepEqual(deps, want) {
+		t.Errorf("got  %+v\nwant %+v", deps, want)
+	}
+}
@@ -0,0 +1,121 @@
+package scanner
+
+import (
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+)
+
+// pipfileNonPackageTables are the Pipfile tables that do not list packages.
+// Every other table is a package category: [packages], [dev-packages] or a
+// custom category.
+var pipfileNonPackageTables = map[string]bool{
+	"source":   true,
+	"requires": true,
+	"pipenv":   true,
+	"scripts":  true,
+}
+
+// ExtractPipfile returns the packages of every category in a Pipfile, each
+// mapped to the URL of the [[source]] it installs from: its index= source,
+// or the first source by default.
+func ExtractPipfile(path string) ([]Dependency, error) {
+	var doc map[string]interface{}
+	if _, err := toml.DecodeFile(path, &doc); err != nil {
+		return nil, err
+	}
+
+	sources := make(map[string]string)
+	defaultSource := ""
+	for i, src := range pipfileSources(doc) {
+		sources[src.Name] = src.URL
+		if i == 0 {
+			defaultSource = src.URL
+		}
+	}
+
+	var deps []Dependency
+	for category, value := range doc {
+		table, ok := value.(map[string]interface{})
+		if !ok || pipfileNonPackageTables[category] {
+			continue
+		}
+
+		for name, spec := range table {
+			dep := pipfileDependency(category, name, spec)
+			if dep.FromRegistry() {
+				dep.Source = defaultSource
+				table, _ := spec.(map[string]interface{})
+				if index, ok := table["index"].(string); ok {
+					dep.Source = sources[index]
+					if dep.Source == "" {
+						fmt.Printf("Warning: %s: %s uses undeclared source %q\n", path, name, index)
+						dep.Source = index
+					}
+				}
+			}
+			deps = append(deps, dep)
+		}
+	}
+
+	sortDependencies(deps)
+	fmt.Printf("Parsed %s: %d packages\n", path, len(deps))
+	return deps, nil
+}
+
+// pipfileDependency converts a Pipfile entry: a version string or a table
+// with version, git, path, file, extras and markers keys.
+func pipfileDependency(category, name string, spec interface{}) Dependency {
+	dep := Dependency{Name: name, Type: category}
+
+	table, ok := spec.(map[string]interface{})
+	if !ok {
+		dep.Version, _ = spec.(string)
+		return dep
+	}
+
+	str := func(key string) string {
+		s, _ := table[key].(string)
+		return s
+	}
+	dep.Version = str("version")
+	dep.Marker = str("markers")
+	if extras, ok := table["extras"].([]interface{}); ok {
+		for _, e := range extras {
+			if s, ok := e.(string); ok {
+				dep.Extras = append(dep.Extras, s)
+			}
+		}
+	}
+
+	switch {
+	case str("git") != "":
+		dep.Kind = SpecGit
+		dep.Resolved = str("git")
+	case str("hg") != "" || str("svn") != "" || str("bzr") != "":
+		dep.Kind = SpecVCS
+		dep.Resolved = str("hg") + str("svn") + str("bzr")
+	case str("path") != "":
+		dep.Kind = SpecFile
+		dep.Resolved = str("path")
+	case str("file") != "":
+		dep.Kind = SpecTarball
+		if !isRequirementURL(str("file")) {
+			dep.Kind = SpecFile
+		}
+		dep.Resolved = str("file")
+	}
+	return dep
+}
+
+// pipfileSources returns the [[source]] entries of a decoded Pipfile.
+func pipfileSources(doc map[string]interface{}) []pyprojectSource {
+	var sources []pyprojectSource
+	entries, _ := doc["source"].([]map[string]interface{})
+	for _, entry := range entries {
+		name, _ := entry["name"].(string)
+		url, _ := entry["url"].(string)
+		sources = append(sources, pyprojectSource{Name: name, URL: url})
+	}
+	return sources
+}
@@ -0,0 +1,246 @@
+package scanner
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/Swayamyadav01/Deptakeover/internal/registry"
+)
+
+type pyproject struct {
+	Project struct {
+		Dependencies         []string            `toml:"dependencies"`
+		OptionalDependencies map[string][]string `toml:"optional-dependencies"`
+	} `toml:"project"`
+	BuildSystem struct {
+		Requires []string `toml:"requires"`
+	} `toml:"build-system"`
+	// DependencyGroups is PEP 735; entries are strings or include tables.
+	DependencyGroups map[string][]interface{} `toml:"dependency-groups"`
+	Tool             struct {
+		Poetry struct {
+			Dependencies    map[string]interface{} `toml:"dependencies"`
+			DevDependencies map[string]interface{} `toml:"dev-dependencies"`
+			Group           map[string]struct {
+				Dependencies map[string]interface{} `toml:"dependencies"`
+			} `toml:"group"`
+			Source []pyprojectSource `toml:"source"`
+		} `toml:"poetry"`
+		PDM struct {
+			DevDependencies map[string][]string `toml:"dev-dependencies"`
+			Source          []pyprojectSource   `toml:"source"`
+		} `toml:"pdm"`
+		Hatch struct {
+			Envs map[string]struct {
+				Dependencies      []string `toml:"dependencies"`
+				ExtraDependencies []string `toml:"extra-dependencies"`
+			} `toml:"envs"`
+		} `toml:"hatch"`
+	} `toml:"tool"`
+}
+
+type pyprojectSource struct {
+	Name string `toml:"name"`
+	URL  string `toml:"url"`
+	// Priority is the Poetry source priority: primary (the default),
+	// supplemental, explicit, or the deprecated default and secondary.
+	// Older Poetry versions mark secondary sources with a boolean instead.
+	Priority  string `toml:"priority"`
+	Secondary bool   `toml:"secondary"`
+	// IncludePackages restricts a PDM source to the packages matching
+	// these glob patterns.
+	IncludePackages []string `toml:"include_packages"`
+}
+
+// repoWide reports whether the source is searched for every package, rather
+// than only for packages that name it (Poetry explicit sources, PDM
+// include_packages) or as a fallback (Poetry supplemental and secondary
+// sources).
+func (src pyprojectSource) repoWide() bool {
+	if len(src.IncludePackages) > 0 {
+		return false
+	}
+	switch src.Priority {
+	case "":
+		return !src.Secondary
+	case "primary", "default":
+		return true
+	}
+	return false
+}
+
+// matches reports whether a PDM source restricted with include_packages
+// serves the package.
+func (src pyprojectSource) matches(name string) bool {
+	name = registry.NormalizePyPIName(name)
+	for _, pattern := range src.IncludePackages {
+		if ok, _ := path.Match(registry.NormalizePyPIName(pattern), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtractPyproject returns the dependencies declared anywhere in a
+// pyproject.toml: PEP 621 dependencies and extras, build requirements,
+// PEP 735 dependency groups, Poetry dependencies and groups, PDM dev groups
+// and Hatch environments. Type records the table each one came from, such as
+// "optional-dependencies.test" or "poetry.group.dev".
+func ExtractPyproject(path string) ([]Dependency, error) {
+	var doc pyproject
+	if _, err := toml.DecodeFile(path, &doc); err != nil {
+		return nil, err
+	}
+
+	var deps []Dependency
+	addPEP508 := func(depType string, requirements []string) {
+		for _, line := range requirements {
+			if dep, ok := parseRequirementLine(strings.TrimSpace(line), depType); ok {
+				deps = append(deps, dep)
+			}
+		}
+	}
+
+	addPEP508("dependencies", doc.Project.Dependencies)
+	for extra, requirements := range doc.Project.OptionalDependencies {
+		addPEP508("optional-dependencies."+extra, requirements)
+	}
+	addPEP508("build-system", doc.BuildSystem.Requires)
+
+	for group, entries := range doc.DependencyGroups {
+		var requirements []string
+		for _, entry := range entries {
+			// {include-group = "..."} entries reference another group.
+			if s, ok := entry.(string); ok {
+				requirements = append(requirements, s)
+			}
+		}
+		addPEP508("dependency-groups."+group, requirements)
+	}
+
+	poetry := doc.Tool.Poetry
+	sources := make(map[string]string)
+	for _, src := range poetry.Source {
+		sources[src.Name] = src.URL
+	}
+	deps = append(deps, poetryDependencies("poetry.dependencies", poetry.Dependencies, sources)...)
+	deps = append(deps, poetryDependencies("poetry.dev-dependencies", poetry.DevDependencies, sources)...)
+	for group, g := range poetry.Group {
+		deps = append(deps, poetryDependencies("poetry.group."+group, g.Dependencies, sources)...)
+	}
+
+	for group, requirements := range doc.Tool.PDM.DevDependencies {
+		addPEP508("pdm.dev-dependencies."+group, requirements)
+	}
+
+	for env, e := range doc.Tool.Hatch.Envs {
+		addPEP508("hatch.envs."+env, e.Dependencies)
+		addPEP508("hatch.envs."+env, e.ExtraDependencies)
+	}
+
+	// PDM installs packages matching a source's include_packages from that
+	// source only.
+	for i := range deps {
+		if !deps[i].FromRegistry() || deps[i].Source != "" {
+			continue
+		}
+		for _, src := range doc.Tool.PDM.Source {
+			if len(src.IncludePackages) > 0 && src.matches(deps[i].Name) {
+				deps[i].Source = src.URL
+				break
+			}
+		}
+	}
+
+	sortDependencies(deps)
+	fmt.Printf("Parsed %s: %d dependencies\n", path, len(deps))
+	return deps, nil
+}
+
+// poetryDependencies converts a Poetry dependency table. Values are a
+// version string, a table ({version, git, path, url, source...}) or a list
+// of tables with per-marker constraints.
+func poetryDependencies(depType string, table map[string]interface{}, sources map[string]string) []Dependency {
+	var deps []Dependency
+	for name, value := range table {
+		if strings.EqualFold(name, "python") {
+			continue
+		}
+
+		specs, ok := value.([]map[string]interface{})
+		if !ok {
+			if list, isList := value.([]interface{}); isList {
+				for _, item := range list {
+					if spec, isTable := item.(map[string]interface{}); isTable {
+						specs = append(specs, spec)
+					}
+				}
+			} else {
+				specs = []map[string]interface{}{{"version": value}}
+				if spec, isTable := value.(map[string]interface{}); isTable {
+					specs = []map[string]interface{}{spec}
+				}
+			}
+		}
+
+		for _, spec := range specs {
+			deps = append(deps, poetryDependency(depType, name, spec, sources))
+		}
+	}
+	return deps
+}
+
+func poetryDependency(depType, name string, spec map[string]interface{}, sources map[string]string) Dependency {
+	str := func(key string) string {
+		s, _ := spec[key].(string)
+		return s
+	}
+
+	dep := Dependency{Name: name, Type: depType, Version: str("version"), Marker: str("markers")}
+	if extras, ok := spec["extras"].([]interface{}); ok {
+		for _, e := range extras {
+			if s, ok := e.(string); ok {
+				dep.Extras = append(dep.Extras, s)
+			}
+		}
+	}
+
+	switch {
+	case str("git") != "":
+		dep.Kind = SpecGit
+		dep.Resolved = str("git")
+	case str("path") != "":
+		dep.Kind = SpecFile
+		dep.Resolved = str("path")
+	case str("url") != "":
+		dep.Kind = SpecTarball
+		dep.Resolved = str("url")
+	case str("source") != "":
+		dep.Source = sources[str("source")]
+		if dep.Source == "" {
+			dep.Source = str("source")
+		}
+	}
+	return dep
+}
+
+// pyprojectSources returns the Poetry and PDM package indexes searched for
+// every package. Sources only used for the packages that name them are
+// recorded per package by ExtractPyproject instead.
+func pyprojectSources(file string) []pyprojectSource {
+	var doc pyproject
+	if _, err := toml.DecodeFile(file, &doc); err != nil {
+		return nil
+	}
+
+	var sources []pyprojectSource
+	for _, src := range append(doc.Tool.Poetry.Source, doc.Tool.PDM.Source...) {
+		if src.repoWide() {
+			sources = append(sources, src)
+		}
+	}
+	return sources
+}
@@ -0,0 +1,39 @@
+package scanner
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPyprojectSources(t *testing.T) {
+	path := filepath.Join("testdata", "pyproject", "pyproject.toml")
+
+	// Explicit, supplemental and secondary Poetry sources and PDM sources
+	// with include_packages do not serve every package.
+	var urls []string
+	for _, src := range pyprojectSources(path) {
+		urls = append(urls, src.URL)
+	}
+	if want := []string{"https://pypi.corp.example/simple"}; !reflect.DeepEqual(urls, want) {
+		t.Errorf("pyprojectSources = %v, want %v", urls, want)
+	}
+
+	deps, err := ExtractPyproject(path)
+	if err != nil {
+		t.Fatalf("ExtractPyproject: %v", err)
+	}
+	got := make(map[string]string)
+	for _, dep := range deps {
+		got[dep.Name] = dep.Source
+	}
+	want := map[string]string{
+		"corp-utils":   "https://pdm.corp.example/simple",
+		"requests":     "",
+		"internal-sdk": "https://explicit.corp.example/simple",
+		"click":        "",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sources = %v, want %v", got, want)
+	}
+}
@@ -1,11 +1,9 @@
 package scanner
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 )
 
@@ -26,10 +24,15 @@ func FindPythonDependencyFiles(repoPath string) []string {
 		}
 
 		// Check for Python dependency files
-		if strings.HasSuffix(info.Name(), "requirements.txt") ||
+		if IsRequirementsFile(path) ||
 			info.Name() == "setup.py" ||
+			info.Name() == "setup.cfg" ||
 			info.Name() == "pyproject.toml" ||
-			info.Name() == "Pipfile" {
+			info.Name() == "Pipfile" ||
+			info.Name() == "tox.ini" ||
+			info.Name() == "noxfile.py" ||
+			isCondaEnvironment(info.Name()) ||
+			pythonLockfileNames[info.Name()] {
 			depFiles = append(depFiles, path)
 			fmt.Printf("Found Python dependency file: %s\n", path)
 		}
@@ -40,170 +43,54 @@ func FindPythonDependencyFiles(repoPath string) []string {
 	return depFiles
 }
 
-func ParseRequirementsText(filePath string) []string {
-	var packages []string
-
-	file, err := os.Open(filePath)
-	if err != nil {
-		return packages
+func ExtractPythonDependencies(depFile string) ([]Dependency, error) {
+	if _, err := os.Stat(depFile); err != nil {
+		return nil, err
 	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	re := regexp.MustCompile(`^([a-zA-Z0-9\-_.]+)`)
-
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-
-		// Skip comments and empty lines
-		if strings.HasPrefix(line, "#") || line == "" {
-			continue
-		}
-
-		// Skip URL entries
-		if strings.HasPrefix(line, "http://") || strings.HasPrefix(line, "https://") {
-			continue
-		}
 
-		// Extract package name (before ==, >=, <=, etc.)
-		matches := re.FindStringSubmatch(line)
-		if len(matches) > 1 {
-			pkgName := strings.ToLower(matches[1])
-			if pkgName != "-e" && pkgName != "." {
-				packages = append(packages, pkgName)
-			}
-		}
+	switch {
+	case IsPythonLockfile(depFile):
+		return ExtractPythonLockfile(depFile)
+	case IsRequirementsFile(depFile):
+		return extractRequirements(depFile)
+	case strings.HasSuffix(depFile, "pyproject.toml"):
+		return ExtractPyproject(depFile)
+	case strings.HasSuffix(depFile, "setup.py"):
+		return ExtractSetupPy(depFile)
+	case strings.HasSuffix(depFile, "setup.cfg"):
+		return ExtractSetupCfg(depFile)
+	case strings.HasSuffix(depFile, "Pipfile"):
+		return ExtractPipfile(depFile)
+	case strings.HasSuffix(depFile, "tox.ini"):
+		return ExtractToxIni(depFile)
+	case strings.HasSuffix(depFile, "noxfile.py"):
+		return ExtractNoxfile(depFile)
+	case isCondaEnvironment(filepath.Base(depFile)):
+		return ExtractCondaEnvironment(depFile)
 	}
 
-	return packages
+	return nil, fmt.Errorf("unsupported dependency file: %s", depFile)
 }
 
-func ParseSetupPy(filePath string) []string {
-	var packages []string
-
-	data, err := os.ReadFile(filePath)
+// extractRequirements parses a requirements file. Packages installed from
+// an index get the file's --index-url as their source; a constraints file's
+// own entries are typed "constraints".
+func extractRequirements(depFile string) ([]Dependency, error) {
+	req, err := ParseRequirementsFile(depFile)
 	if err != nil {
-		return packages
+		return nil, err
 	}
 
-	contentStr := string(data)
-
-	// Match install_requires = [...]  or requires = [...]
-	re := regexp.MustCompile(`(?:install_requires|requires)\s*=\s*\[(.*?)\]`)
-	matches := re.FindStringSubmatch(contentStr)
-
-	if len(matches) > 1 {
-		content := matches[1]
-		// Extract package names from strings
-		pkgRe := regexp.MustCompile(`["']([a-zA-Z0-9\-_]+)`)
-		for _, match := range pkgRe.FindAllStringSubmatch(content, -1) {
-			if len(match) > 1 {
-				packages = append(packages, strings.ToLower(match[1]))
-			}
+	deps := req.Requirements
+	for i := range deps {
+		if deps[i].FromRegistry() {
+			deps[i].Source = req.IndexURL
 		}
-	}
-
-	return packages
-}
-
-func ParsePyprojectToml(filePath string) []string {
-	var packages []string
-
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		return packages
-	}
-
-	contentStr := string(data)
-
-	// Match dependencies section
-	re := regexp.MustCompile(`dependencies\s*=\s*\[(.*?)\]`)
-	matches := re.FindStringSubmatch(contentStr)
-
-	if len(matches) > 1 {
-		content := matches[1]
-		// Extract package names
-		pkgRe := regexp.MustCompile(`["']([a-zA-Z0-9\-_]+)`)
-		for _, match := range pkgRe.FindAllStringSubmatch(content, -1) {
-			if len(match) > 1 {
-				packages = append(packages, strings.ToLower(match[1]))
-			}
+		if deps[i].Type == "" && IsConstraintsFile(depFile) {
+			deps[i].Type = "constraints"
 		}
 	}
 
-	return packages
-}
-
-func ParsePipfile(filePath string) []string {
-	var packages []string
-
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		return packages
-	}
-
-	contentStr := string(data)
-
-	// Match packages section
-	re := regexp.MustCompile(`\[packages\](.*?)(?:\[|$)`)
-	matches := re.FindStringSubmatch(contentStr)
-
-	if len(matches) > 1 {
-		content := matches[1]
-		// Extract package names
-		pkgRe := regexp.MustCompile(`^([a-zA-Z0-9\-_]+)\s*=`)
-		for _, line := range strings.Split(content, "\n") {
-			match := pkgRe.FindStringSubmatch(line)
-			if len(match) > 1 {
-				packages = append(packages, strings.ToLower(match[1]))
-			}
-		}
-	}
-
-	return packages
-}
-
-func ExtractAllPythonDependencies(repoPath string) map[string][]string {
-	depsByFile := make(map[string][]string)
-
-	depFiles := FindPythonDependencyFiles(repoPath)
-
-	for _, depFile := range depFiles {
-		var deps []string
-
-		switch {
-		case strings.HasSuffix(depFile, "requirements.txt"):
-			deps = ParseRequirementsText(depFile)
-		case strings.HasSuffix(depFile, "setup.py"):
-			deps = ParseSetupPy(depFile)
-		case strings.HasSuffix(depFile, "pyproject.toml"):
-			deps = ParsePyprojectToml(depFile)
-		case strings.HasSuffix(depFile, "Pipfile"):
-			deps = ParsePipfile(depFile)
-		}
-
-		if len(deps) > 0 {
-			depsByFile[depFile] = deps
-		}
-	}
-
-	return depsByFile
-}
-
-func GetAllUniquePythonDeps(repoPath string) []string {
-	depsByFile := ExtractAllPythonDependencies(repoPath)
-
-	uniqueMap := make(map[string]bool)
-	for _, deps := range depsByFile {
-		for _, name := range deps {
-			uniqueMap[name] = true
-		}
-	}
-
-	var uniqueDeps []string
-	for name := range uniqueMap {
-		uniqueDeps = append(uniqueDeps, name)
-	}
-
-	return uniqueDeps
+	fmt.Printf("Parsed %s: %d requirements\n", depFile, len(deps))
+	return deps, nil
 }
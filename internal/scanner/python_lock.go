@@ -0,0 +1,225 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// pythonLockfileNames are the lockfiles understood by ExtractPythonLockfile.
+var pythonLockfileNames = map[string]bool{
+	"poetry.lock":  true,
+	"Pipfile.lock": true,
+	"uv.lock":      true,
+	"pdm.lock":     true,
+}
+
+// IsPythonLockfile reports whether path is a Poetry, Pipenv, uv or PDM lockfile.
+func IsPythonLockfile(path string) bool {
+	return pythonLockfileNames[filepath.Base(path)]
+}
+
+// ExtractPythonLockfile returns every package pinned in a lockfile that is
+// installed from a package index, with the index it was locked against.
+// Git, path and URL packages are left out since no index serves them.
+func ExtractPythonLockfile(lockPath string) ([]Dependency, error) {
+	var deps []Dependency
+	var err error
+
+	switch filepath.Base(lockPath) {
+	case "poetry.lock":
+		deps, err = parsePoetryLock(lockPath)
+	case "Pipfile.lock":
+		deps, err = parsePipfileLock(lockPath)
+	case "uv.lock":
+		deps, err = parseUVLock(lockPath)
+	case "pdm.lock":
+		deps, err = parsePDMLock(lockPath)
+	default:
+		return nil, fmt.Errorf("unsupported lockfile: %s", lockPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sortDependencies(deps)
+	fmt.Printf("Parsed %s: %d locked packages\n", lockPath, len(deps))
+	return deps, nil
+}
+
+type poetryLock struct {
+	Package []struct {
+		Name     string   `toml:"name"`
+		Version  string   `toml:"version"`
+		Category string   `toml:"category"`
+		Groups   []string `toml:"groups"`
+		Source   struct {
+			Type      string `toml:"type"`
+			URL       string `toml:"url"`
+			Reference string `toml:"reference"`
+		} `toml:"source"`
+	} `toml:"package"`
+}
+
+func parsePoetryLock(lockPath string) ([]Dependency, error) {
+	var lock poetryLock
+	if _, err := toml.DecodeFile(lockPath, &lock); err != nil {
+		return nil, err
+	}
+
+	var deps []Dependency
+	for _, pkg := range lock.Package {
+		// Packages without a source come from PyPI; "legacy" is a custom
+		// index. Anything else (git, directory, file, url) is not indexed.
+		if pkg.Source.Type != "" && pkg.Source.Type != "legacy" {
+			continue
+		}
+
+		group := pkg.Category
+		if len(pkg.Groups) > 0 {
+			group = strings.Join(pkg.Groups, ",")
+		}
+
+		deps = append(deps, Dependency{
+			Name:    pkg.Name,
+			Version: pkg.Version,
+			Type:    group,
+			Source:  pkg.Source.URL,
+		})
+	}
+	return deps, nil
+}
+
+type pipfileLock struct {
+	Meta struct {
+		Sources []struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"sources"`
+	} `json:"_meta"`
+	Default map[string]pipfileLockEntry `json:"default"`
+	Develop map[string]pipfileLockEntry `json:"develop"`
+}
+
+type pipfileLockEntry struct {
+	Version  string `json:"version"`
+	Index    string `json:"index"`
+	Git      string `json:"git"`
+	Path     string `json:"path"`
+	File     string `json:"file"`
+	Editable bool   `json:"editable"`
+}
+
+func parsePipfileLock(lockPath string) ([]Dependency, error) {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var lock pipfileLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	sources := make(map[string]string)
+	defaultSource := ""
+	for i, src := range lock.Meta.Sources {
+		sources[src.Name] = src.URL
+		if i == 0 {
+			defaultSource = src.URL
+		}
+	}
+
+	var deps []Dependency
+	for section, entries := range map[string]map[string]pipfileLockEntry{"default": lock.Default, "develop": lock.Develop} {
+		for name, entry := range entries {
+			if entry.Git != "" || entry.Path != "" || entry.File != "" || entry.Editable {
+				continue
+			}
+
+			source := defaultSource
+			if entry.Index != "" {
+				source = sources[entry.Index]
+			}
+
+			deps = append(deps, Dependency{
+				Name:    name,
+				Version: strings.TrimPrefix(entry.Version, "=="),
+				Type:    section,
+				Source:  source,
+			})
+		}
+	}
+	return deps, nil
+}
+
+type uvLock struct {
+	Package []struct {
+		Name    string `toml:"name"`
+		Version string `toml:"version"`
+		Source  struct {
+			Registry string `toml:"registry"`
+		} `toml:"source"`
+	} `toml:"package"`
+}
+
+func parseUVLock(lockPath string) ([]Dependency, error) {
+	var lock uvLock
+	if _, err := toml.DecodeFile(lockPath, &lock); err != nil {
+		return nil, err
+	}
+
+	var deps []Dependency
+	for _, pkg := range lock.Package {
+		// Only registry sources are indexed; editable, virtual, git, path
+		// and url sources (including the project itself) are skipped.
+		if pkg.Source.Registry == "" {
+			continue
+		}
+
+		deps = append(deps, Dependency{
+			Name:    pkg.Name,
+			Version: pkg.Version,
+			Source:  pkg.Source.Registry,
+		})
+	}
+	return deps, nil
+}
+
+type pdmLock struct {
+	Package []struct {
+		Name     string   `toml:"name"`
+		Version  string   `toml:"version"`
+		Groups   []string `toml:"groups"`
+		Git      string   `toml:"git"`
+		Path     string   `toml:"path"`
+		URL      string   `toml:"url"`
+		Editable bool     `toml:"editable"`
+	} `toml:"package"`
+}
+
+func parsePDMLock(lockPath string) ([]Dependency, error) {
+	var lock pdmLock
+	if _, err := toml.DecodeFile(lockPath, &lock); err != nil {
+		return nil, err
+	}
+
+	var deps []Dependency
+	for _, pkg := range lock.Package {
+		if pkg.Git != "" || pkg.Path != "" || pkg.URL != "" || pkg.Editable {
+			continue
+		}
+
+		// PDM records sources in pyproject.toml rather than the lockfile.
+		deps = append(deps, Dependency{
+			Name:    pkg.Name,
+			Version: pkg.Version,
+			Type:    strings.Join(pkg.Groups, ","),
+		})
+	}
+	return deps, nil
+}
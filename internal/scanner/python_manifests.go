@@ -0,0 +1,231 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IsRequirementsFile reports whether path is a pip requirements file under
+// any of its common names: requirements.txt, requirements-dev.txt,
+// dev-requirements.in, constraints.txt, or any .txt/.in file in a
+// requirements/ directory (pip-tools inputs and their compiled outputs).
+func IsRequirementsFile(path string) bool {
+	name := strings.ToLower(filepath.Base(path))
+	ext := filepath.Ext(name)
+	if ext != ".txt" && ext != ".in" {
+		return false
+	}
+	return strings.Contains(name, "requirements") || strings.Contains(name, "constraints") ||
+		strings.EqualFold(filepath.Base(filepath.Dir(path)), "requirements")
+}
+
+// IsConstraintsFile reports whether path is a pip constraints file, whose
+// entries pin versions without being installed.
+func IsConstraintsFile(path string) bool {
+	return IsRequirementsFile(path) && strings.Contains(strings.ToLower(filepath.Base(path)), "constraints")
+}
+
+// isCondaEnvironment reports whether name is a conda environment file:
+// environment.yml, environment-dev.yaml and the like.
+func isCondaEnvironment(name string) bool {
+	ext := filepath.Ext(name)
+	return strings.HasPrefix(name, "environment") && (ext == ".yml" || ext == ".yaml")
+}
+
+// embeddedRequirements parses requirements-file lines embedded in another
+// manifest. Packages installed from an index get its --index-url as their
+// source, as in a requirements file.
+func embeddedRequirements(path string, lines []string, depType string) []Dependency {
+	var req RequirementsFile
+	visited := make(map[string]bool)
+	if abs, err := filepath.Abs(path); err == nil {
+		visited[abs] = true
+	}
+	parseRequirementLines(path, lines, depType, &req, visited, path)
+
+	deps := req.Requirements
+	for i := range deps {
+		if deps[i].FromRegistry() {
+			deps[i].Source = req.IndexURL
+		}
+	}
+	return deps
+}
+
+// ExtractCondaEnvironment returns the packages in the pip: list of a conda
+// environment.yml. The conda packages around it come from conda channels,
+// not PyPI, so they are left out.
+func ExtractCondaEnvironment(path string) ([]Dependency, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var env struct {
+		Dependencies []interface{} `yaml:"dependencies"`
+	}
+	if err := yaml.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, entry := range env.Dependencies {
+		table, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		pip, _ := table["pip"].([]interface{})
+		for _, item := range pip {
+			if s, ok := item.(string); ok {
+				lines = append(lines, s)
+			}
+		}
+	}
+
+	deps := embeddedRequirements(path, lines, "pip")
+	sortDependencies(deps)
+	fmt.Printf("Parsed %s: %d pip packages\n", path, len(deps))
+	return deps, nil
+}
+
+// toxFactorPrefix matches a tox factor condition such as "py38,py39: " or
+// "!lint: " in front of a dependency.
+var toxFactorPrefix = regexp.MustCompile(`^[\w.,!{}-]+:\s+`)
+
+// ExtractToxIni returns the deps of every [testenv] and [testenv:NAME]
+// section of a tox.ini, typed by section. Factor conditions are dropped
+// (the dependency is kept for every environment) and {toxinidir} is
+// resolved; references to other sections' settings are skipped.
+func ExtractToxIni(path string) ([]Dependency, error) {
+	lines, err := readLines(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var deps []Dependency
+	var section string
+	var value []string
+	inDeps := false
+	flush := func() {
+		if inDeps {
+			deps = append(deps, embeddedRequirements(path, value, section)...)
+		}
+		inDeps, value = false, nil
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") {
+			continue
+		}
+
+		if line[0] == ' ' || line[0] == '\t' {
+			if inDeps {
+				value = append(value, toxDependencyLine(trimmed))
+			}
+			continue
+		}
+
+		flush()
+		if strings.HasPrefix(trimmed, "[") {
+			section = strings.Trim(trimmed, "[]")
+			continue
+		}
+		key, v, found := strings.Cut(trimmed, "=")
+		if found && strings.TrimSpace(key) == "deps" && strings.HasPrefix(section, "testenv") {
+			inDeps = true
+			if v = strings.TrimSpace(v); v != "" {
+				value = append(value, toxDependencyLine(v))
+			}
+		}
+	}
+	flush()
+
+	sortDependencies(deps)
+	fmt.Printf("Parsed %s: %d dependencies\n", path, len(deps))
+	return deps, nil
+}
+
+func toxDependencyLine(line string) string {
+	line = toxFactorPrefix.ReplaceAllString(line, "")
+	if strings.HasPrefix(line, "{[") {
+		return ""
+	}
+	line = strings.ReplaceAll(line, "{toxinidir}/", "")
+	return strings.ReplaceAll(line, "{toxinidir}", ".")
+}
+
+// ExtractNoxfile statically extracts the packages passed to
+// session.install() in a noxfile.py. Arguments are evaluated like setup()
+// arguments, so literals and module-level variables (including *args) are
+// understood; -r and -c arguments are followed as requirements files.
+func ExtractNoxfile(path string) ([]Dependency, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &pyParser{tokens: tokenizePython(string(data)), vars: make(map[string]pyValue)}
+	var args []string
+
+	for p.pos < len(p.tokens) {
+		tok := p.next()
+
+		if tok.kind == pyName && tok.bol && p.peek().text == "=" {
+			p.next()
+			p.vars[tok.text] = p.expr()
+			continue
+		}
+
+		if tok.kind != pyName || tok.text != "install" || p.pos < 2 || p.tokens[p.pos-2].text != "." || p.peek().text != "(" {
+			continue
+		}
+
+		p.next()
+		for p.pos < len(p.tokens) && p.peek().text != ")" {
+			if p.peek().kind == pyName && p.peekAt(1).text == "=" {
+				// Keyword arguments (env=, silent=) are not packages.
+				p.next()
+				p.next()
+				p.expr()
+			} else {
+				if p.peek().text == "*" {
+					p.next()
+				}
+				lines, ok := p.expr().requirementLines()
+				if !ok {
+					fmt.Printf("Warning: %s: session.install() argument is computed dynamically and cannot be resolved statically\n", path)
+				}
+				args = append(args, lines...)
+			}
+			if p.peek().text == "," {
+				p.next()
+			}
+		}
+	}
+
+	deps := embeddedRequirements(path, installArgLines(args), "session.install")
+	sortDependencies(deps)
+	fmt.Printf("Parsed %s: %d packages\n", path, len(deps))
+	return deps, nil
+}
+
+// installArgLines turns "pip install" arguments into requirements-file
+// lines, joining options like "-r" with their separate value.
+func installArgLines(args []string) []string {
+	var lines []string
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		if requirementOptions[arg] && i+1 < len(args) {
+			i++
+			arg += " " + strings.TrimSpace(args[i])
+		}
+		lines = append(lines, arg)
+	}
+	return lines
+}
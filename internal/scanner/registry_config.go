@@ -0,0 +1,300 @@
+package scanner
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PrivateRegistry is a non-public package registry that a repository's
+// configuration sends some or all of its packages to.
+type PrivateRegistry struct {
+	// File is the configuration file that declares the registry.
+	File string `json:"file"`
+	// Scope is the namespace served by the registry (an npm "@scope"), or
+	// empty when the registry serves any package name.
+	Scope string `json:"scope,omitempty"`
+	URL   string `json:"url"`
+}
+
+// publicRegistryHosts are the hosts of the public registries; configs that
+// point at them (or mirror them under these names) are not private.
+var publicRegistryHosts = map[string]bool{
+	"registry.npmjs.org":     true,
+	"registry.yarnpkg.com":   true,
+	"registry.npmmirror.com": true,
+	"pypi.org":               true,
+	"pypi.python.org":        true,
+	"files.pythonhosted.org": true,
+	"packagist.org":          true,
+	"repo.packagist.org":     true,
+}
+
+// IsPrivateRegistry reports whether a registry URL points somewhere other
+// than a public registry.
+func IsPrivateRegistry(raw string) bool {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return !publicRegistryHosts[strings.ToLower(u.Hostname())]
+}
+
+// findConfigFiles returns the files under repoPath whose path matches,
+// skipping dependency and virtualenv directories. Hidden directories are
+// walked since configs like .pip/pip.conf live in them, except .git.
+func findConfigFiles(repoPath string, match func(path string) bool) []string {
+	var files []string
+
+	filepath.Walk(repoPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if info.IsDir() {
+			switch info.Name() {
+			case ".git", "node_modules", "vendor", ".venv", "venv", "site-packages":
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if match(path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+
+	sort.Strings(files)
+	return files
+}
+
+// FindNPMPrivateRegistries returns the private registries configured in
+// .npmrc, .yarnrc and .yarnrc.yml files under repoPath.
+func FindNPMPrivateRegistries(repoPath string) []PrivateRegistry {
+	var registries []PrivateRegistry
+	add := func(file, scope, registryURL string) {
+		registryURL = strings.Trim(strings.TrimSpace(registryURL), `"'`)
+		if IsPrivateRegistry(registryURL) {
+			if scope != "" && !strings.HasPrefix(scope, "@") {
+				scope = "@" + scope
+			}
+			registries = append(registries, PrivateRegistry{File: file, Scope: scope, URL: registryURL})
+		}
+	}
+
+	for _, path := range findConfigFiles(repoPath, func(path string) bool {
+		name := filepath.Base(path)
+		return name == ".npmrc" || name == ".yarnrc" || name == ".yarnrc.yml"
+	}) {
+		switch filepath.Base(path) {
+		case ".npmrc":
+			// registry=URL and @scope:registry=URL
+			for _, kv := range readKeyValues(path, "=") {
+				if kv[0] == "registry" {
+					add(path, "", kv[1])
+				} else if scope, ok := strings.CutSuffix(kv[0], ":registry"); ok {
+					add(path, scope, kv[1])
+				}
+			}
+		case ".yarnrc":
+			// Yarn classic: registry "URL" and "@scope:registry" "URL"
+			for _, kv := range readKeyValues(path, " ") {
+				key := strings.Trim(kv[0], `"`)
+				if key == "registry" {
+					add(path, "", kv[1])
+				} else if scope, ok := strings.CutSuffix(key, ":registry"); ok {
+					add(path, scope, kv[1])
+				}
+			}
+		case ".yarnrc.yml":
+			data, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			var rc struct {
+				NPMRegistryServer string `yaml:"npmRegistryServer"`
+				NPMScopes         map[string]struct {
+					NPMRegistryServer string `yaml:"npmRegistryServer"`
+				} `yaml:"npmScopes"`
+			}
+			if yaml.Unmarshal(data, &rc) != nil {
+				continue
+			}
+			add(path, "", rc.NPMRegistryServer)
+			for scope, cfg := range rc.NPMScopes {
+				add(path, scope, cfg.NPMRegistryServer)
+			}
+		}
+	}
+
+	sortRegistries(registries)
+	return registries
+}
+
+// FindPythonPrivateRegistries returns the private package indexes configured
+// in pip.conf and pip.ini files, in the index options of requirements files
+// and in Poetry and PDM sources under repoPath. Pipfile sources, Poetry
+// explicit and supplemental sources and PDM sources restricted with
+// include_packages are not included: they only serve the packages that name
+// or match them, which the manifest parsers record per package.
+func FindPythonPrivateRegistries(repoPath string) []PrivateRegistry {
+	var registries []PrivateRegistry
+	add := func(file, indexURL string) {
+		if IsPrivateRegistry(indexURL) {
+			registries = append(registries, PrivateRegistry{File: file, URL: indexURL})
+		}
+	}
+
+	for _, path := range findConfigFiles(repoPath, func(path string) bool {
+		name := filepath.Base(path)
+		return name == "pip.conf" || name == "pip.ini" || name == "pyproject.toml" || IsRequirementsFile(path)
+	}) {
+		if filepath.Base(path) == "pyproject.toml" {
+			for _, src := range pyprojectSources(path) {
+				add(path, src.URL)
+			}
+			continue
+		}
+
+		if IsRequirementsFile(path) {
+			req, _ := ParseRequirementsFile(path)
+			add(path, req.IndexURL)
+			for _, indexURL := range req.ExtraIndexURLs {
+				add(path, indexURL)
+			}
+			continue
+		}
+
+		// index-url and extra-index-url, whose value may continue on
+		// indented lines with one URL each.
+		var key string
+		lines, _ := readLines(path)
+		for _, line := range lines {
+			trimmed := strings.TrimSpace(line)
+			if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") || strings.HasPrefix(trimmed, "[") {
+				key = ""
+				continue
+			}
+			if line[0] == ' ' || line[0] == '\t' {
+				if key == "index-url" || key == "extra-index-url" {
+					for _, u := range strings.Fields(trimmed) {
+						add(path, u)
+					}
+				}
+				continue
+			}
+
+			k, v, found := strings.Cut(trimmed, "=")
+			if !found {
+				k, v, found = strings.Cut(trimmed, ":")
+			}
+			key = strings.TrimSpace(k)
+			if found && (key == "index-url" || key == "extra-index-url") {
+				for _, u := range strings.Fields(v) {
+					add(path, u)
+				}
+			}
+		}
+	}
+
+	sortRegistries(registries)
+	return registries
+}
+
+// FindComposerPrivateRegistries returns the private Composer repositories
+// declared in composer.json files, and the hosts given credentials in
+// auth.json files, under repoPath.
+func FindComposerPrivateRegistries(repoPath string) []PrivateRegistry {
+	var registries []PrivateRegistry
+
+	for _, path := range findConfigFiles(repoPath, func(path string) bool {
+		name := filepath.Base(path)
+		return name == "composer.json" || name == "auth.json"
+	}) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		if filepath.Base(path) == "composer.json" {
+			var composer ComposerJSON
+			if json.Unmarshal(data, &composer) != nil {
+				continue
+			}
+			for _, repo := range composerRepositories(composer.Repositories) {
+				if repo.Type == "composer" && IsPrivateRegistry(repo.URL) {
+					registries = append(registries, PrivateRegistry{File: path, URL: repo.URL})
+				}
+			}
+			continue
+		}
+
+		// auth.json holds credentials per host; any host other than the
+		// public ones is a private repository.
+		var auth map[string]map[string]json.RawMessage
+		if json.Unmarshal(data, &auth) != nil {
+			continue
+		}
+		for _, section := range []string{"http-basic", "bearer"} {
+			for host := range auth[section] {
+				if u := "https://" + host; IsPrivateRegistry(u) {
+					registries = append(registries, PrivateRegistry{File: path, URL: u})
+				}
+			}
+		}
+	}
+
+	sortRegistries(registries)
+	return registries
+}
+
+// readKeyValues splits each non-comment line of a config file at the first
+// sep into a trimmed key and value.
+func readKeyValues(path, sep string) [][2]string {
+	var pairs [][2]string
+	lines, _ := readLines(path)
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if k, v, found := strings.Cut(line, sep); found {
+			pairs = append(pairs, [2]string{strings.TrimSpace(k), strings.TrimSpace(v)})
+		}
+	}
+	return pairs
+}
+
+func readLines(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), "\r"))
+	}
+	return lines, scanner.Err()
+}
+
+func sortRegistries(registries []PrivateRegistry) {
+	sort.Slice(registries, func(i, j int) bool {
+		if registries[i].File != registries[j].File {
+			return registries[i].File < registries[j].File
+		}
+		if registries[i].Scope != registries[j].Scope {
+			return registries[i].Scope < registries[j].Scope
+		}
+		return registries[i].URL < registries[j].URL
+	})
+}
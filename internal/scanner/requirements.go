@@ -0,0 +1,283 @@
+package scanner
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// RequirementsFile is a parsed pip requirements file, includes resolved.
+type RequirementsFile struct {
+	Requirements []Dependency
+	// Index options, in the order they appear. pip applies them to every
+	// requirement regardless of position.
+	IndexURL       string
+	ExtraIndexURLs []string
+	FindLinks      []string
+	NoIndex        bool
+}
+
+// requirementName matches the start of a PEP 508 requirement: the project
+// name and optional extras.
+var requirementName = regexp.MustCompile(`^([A-Za-z0-9](?:[A-Za-z0-9._-]*[A-Za-z0-9])?)\s*(?:\[([^\]]*)\])?\s*`)
+
+// vcsSchemes are the prefixes pip accepts for VCS requirements.
+var vcsSchemes = []string{"git+", "hg+", "svn+", "bzr+"}
+
+// requirementOptions are the pip options that take a value, so that the
+// value is not mistaken for a requirement.
+var requirementOptions = map[string]bool{
+	"-r":                true,
+	"--requirement":     true,
+	"-c":                true,
+	"--constraint":      true,
+	"-e":                true,
+	"--editable":        true,
+	"-i":                true,
+	"--index-url":       true,
+	"--extra-index-url": true,
+	"-f":                true,
+	"--find-links":      true,
+	"--trusted-host":    true,
+	"--no-binary":       true,
+	"--only-binary":     true,
+	"--use-feature":     true,
+	"--hash":            true,
+	"--global-option":   true,
+	"--install-option":  true,
+	"--config-settings": true,
+}
+
+// ParseRequirementsFile parses a pip requirements file the way pip reads
+// it: continuation lines are joined, comments stripped, -r and -c includes
+// followed (relative to the including file, each file once), and index
+// options recorded. Requirements from constraint files get the type
+// "constraints" since pip never installs them on their own.
+func ParseRequirementsFile(path string) (RequirementsFile, error) {
+	var req RequirementsFile
+	err := parseRequirements(path, "", &req, make(map[string]bool), path)
+	return req, err
+}
+
+func parseRequirements(path, depType string, req *RequirementsFile, visited map[string]bool, root string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if visited[abs] {
+		return nil
+	}
+	visited[abs] = true
+
+	lines, err := readLines(path)
+	if err != nil {
+		return err
+	}
+	parseRequirementLines(path, lines, depType, req, visited, root)
+	return nil
+}
+
+// parseRequirementLines parses requirements-file lines read from path, which
+// may be another manifest embedding them (environment.yml, tox.ini). Includes
+// are resolved relative to path.
+func parseRequirementLines(path string, lines []string, depType string, req *RequirementsFile, visited map[string]bool, root string) {
+	for _, line := range joinContinuations(lines) {
+		line = stripRequirementComment(line)
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "-") {
+			opt, value := splitRequirementOption(line)
+			switch opt {
+			case "-r", "--requirement", "-c", "--constraint":
+				if strings.Contains(value, "://") {
+					fmt.Printf("Warning: skipping remote include %s in %s\n", value, path)
+					continue
+				}
+				includeType := depType
+				if opt == "-c" || opt == "--constraint" {
+					includeType = "constraints"
+				}
+				include := filepath.Join(filepath.Dir(path), value)
+				if err := parseRequirements(include, includeType, req, visited, root); err != nil {
+					fmt.Printf("Warning: %s includes %s: %v\n", path, value, err)
+				}
+			case "-i", "--index-url":
+				req.IndexURL = value
+			case "--extra-index-url":
+				req.ExtraIndexURLs = append(req.ExtraIndexURLs, value)
+			case "-f", "--find-links":
+				req.FindLinks = append(req.FindLinks, value)
+			case "--no-index":
+				req.NoIndex = true
+			case "-e", "--editable":
+				if dep, ok := parseRequirementLine(value, depType); ok {
+					req.Requirements = append(req.Requirements, withFile(dep, path, root))
+				}
+			}
+			continue
+		}
+
+		if dep, ok := parseRequirementLine(line, depType); ok {
+			req.Requirements = append(req.Requirements, withFile(dep, path, root))
+		}
+	}
+}
+
+// parseRequirementLine parses a requirement specifier, URL or path with any
+// per-requirement options (--hash...) already allowed to trail it.
+func parseRequirementLine(line, depType string) (Dependency, bool) {
+	// Per-requirement options start at the first " -" / " --".
+	if i := strings.Index(line, " -"); i >= 0 {
+		line = strings.TrimSpace(line[:i])
+	}
+
+	dep := Dependency{Type: depType}
+
+	m := requirementName.FindStringSubmatch(line)
+	direct := m != nil && strings.HasPrefix(line[len(m[0]):], "@")
+	if !direct && (isRequirementURL(line) || isRequirementPath(line)) {
+		return urlRequirement(dep, "", line)
+	}
+	if m == nil {
+		return dep, false
+	}
+	dep.Name = m[1]
+	if m[2] != "" {
+		for _, extra := range strings.Split(m[2], ",") {
+			if extra = strings.TrimSpace(extra); extra != "" {
+				dep.Extras = append(dep.Extras, extra)
+			}
+		}
+	}
+	rest := strings.TrimSpace(line[len(m[0]):])
+
+	// PEP 508 direct reference: "name @ url ; marker". The marker must be
+	// separated from the URL by whitespace.
+	if after, ok := strings.CutPrefix(rest, "@"); ok {
+		target, marker, _ := strings.Cut(strings.TrimSpace(after), " ;")
+		dep.Marker = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(marker), ";"))
+		return urlRequirement(dep, dep.Name, strings.TrimSpace(target))
+	}
+
+	spec, marker, _ := strings.Cut(rest, ";")
+	dep.Version = strings.Join(strings.Fields(spec), "")
+	dep.Marker = strings.TrimSpace(marker)
+	return dep, true
+}
+
+// urlRequirement classifies a URL or path requirement, naming it after its
+// #egg= fragment when name is empty.
+func urlRequirement(dep Dependency, name, target string) (Dependency, bool) {
+	if _, fragment, found := strings.Cut(target, "#"); found {
+		for _, part := range strings.Split(fragment, "&") {
+			if egg, ok := strings.CutPrefix(part, "egg="); ok && name == "" {
+				name = egg
+				if m := requirementName.FindStringSubmatch(egg); m != nil {
+					name = m[1]
+				}
+			}
+		}
+	}
+
+	dep.Name = name
+	dep.Resolved = target
+	switch {
+	case strings.HasPrefix(target, "git+"):
+		dep.Kind = SpecGit
+	case hasVCSScheme(target):
+		dep.Kind = SpecVCS
+	case isRequirementURL(target) && !strings.HasPrefix(target, "file:"):
+		dep.Kind = SpecTarball
+	default:
+		dep.Kind = SpecFile
+	}
+	if dep.Name == "" {
+		dep.Name = target
+	}
+	return dep, true
+}
+
+func withFile(dep Dependency, path, root string) Dependency {
+	if path != root {
+		dep.File = path
+	}
+	return dep
+}
+
+// joinContinuations merges lines ending in a backslash with the next one.
+func joinContinuations(lines []string) []string {
+	var joined []string
+	var current strings.Builder
+	for _, line := range lines {
+		if strings.HasSuffix(line, `\`) {
+			current.WriteString(strings.TrimSuffix(line, `\`))
+			continue
+		}
+		current.WriteString(line)
+		joined = append(joined, current.String())
+		current.Reset()
+	}
+	if current.Len() > 0 {
+		joined = append(joined, current.String())
+	}
+	return joined
+}
+
+// stripRequirementComment removes a "#" comment, which pip only recognizes
+// at the start of a line or after whitespace so that URL fragments survive.
+func stripRequirementComment(line string) string {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "#") {
+		return ""
+	}
+	for i := 1; i < len(line); i++ {
+		if line[i] == '#' && (line[i-1] == ' ' || line[i-1] == '\t') {
+			return strings.TrimSpace(line[:i])
+		}
+	}
+	return line
+}
+
+// splitRequirementOption splits "-r file", "-rfile" and "--opt=value"
+// forms into the option and its value.
+func splitRequirementOption(line string) (string, string) {
+	if strings.HasPrefix(line, "--") {
+		name, value, found := strings.Cut(line, "=")
+		if !found || strings.ContainsAny(name, " \t") {
+			name, value, _ = strings.Cut(line, " ")
+		}
+		if !requirementOptions[name] {
+			value = ""
+		}
+		return name, strings.TrimSpace(value)
+	}
+
+	if len(line) > 2 && line[2] != ' ' && line[2] != '\t' && requirementOptions[line[:2]] {
+		return line[:2], strings.TrimSpace(line[2:])
+	}
+	name, value, _ := strings.Cut(line, " ")
+	return name, strings.TrimSpace(value)
+}
+
+func isRequirementURL(s string) bool {
+	return strings.Contains(s, "://") || hasVCSScheme(s)
+}
+
+func hasVCSScheme(s string) bool {
+	for _, scheme := range vcsSchemes {
+		if strings.HasPrefix(s, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRequirementPath reports whether a requirement is a local directory or
+// archive rather than a project name.
+func isRequirementPath(s string) bool {
+	return s == "." || strings.HasPrefix(s, "./") || strings.HasPrefix(s, "../") || strings.HasPrefix(s, "/") ||
+		strings.HasPrefix(s, "~") || strings.HasSuffix(s, ".whl") || strings.HasSuffix(s, ".tar.gz") || strings.HasSuffix(s, ".zip")
+}
@@ -0,0 +1,115 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseRequirementsFile(t *testing.T) {
+	dir := filepath.Join("testdata", "requirements")
+	base := filepath.Join(dir, "nested", "base.txt")
+	more := filepath.Join(dir, "nested", "more.txt")
+
+	req, err := ParseRequirementsFile(filepath.Join(dir, "requirements.txt"))
+	if err != nil {
+		t.Fatalf("ParseRequirementsFile: %v", err)
+	}
+
+	// Includes are parsed where they appear; base.txt and more.txt include
+	// each other and the top-level file, and each is read once.
+	want := []Dependency{
+		{Name: "hg-pkg", Kind: SpecVCS, Resolved: "hg+https://hg.example.com/repo#egg=hg-pkg", File: more},
+		{Name: "click", Version: "==8.1.7", File: base},
+		{Name: "urllib3", Version: "<2", Type: "constraints", File: filepath.Join(dir, "constraints.txt")},
+		{Name: "requests", Version: ">=2.28", Extras: []string{"security", "socks"}, Marker: `python_version >= "3.8"`},
+		{Name: "Django", Version: ">=4.2,<5.0"},
+		{Name: "editable-lib", Kind: SpecGit, Resolved: "git+https://github.com/someone/editable-lib.git@main#egg=editable-lib"},
+		{Name: "./local-package", Kind: SpecFile, Resolved: "./local-package"},
+		{Name: "mylib", Kind: SpecTarball, Resolved: "https://files.example.com/mylib-1.0.tar.gz", Marker: `sys_platform == "linux"`},
+		{Name: "otherlib", Kind: SpecGit, Resolved: "git+https://github.com/someone/otherlib.git@v1.0"},
+		{Name: "zipped-pkg", Kind: SpecTarball, Resolved: "https://example.com/archives/pkg-2.0.zip#egg=zipped-pkg"},
+		{Name: "hashed", Version: "==1.0"},
+	}
+	if !reflect.DeepEqual(req.Requirements, want) {
+		t.Errorf("requirements:\ngot  %+v\nwant %+v", req.Requirements, want)
+	}
+
+	if req.IndexURL != "https://pypi.corp.example/simple" {
+		t.Errorf("IndexURL = %q", req.IndexURL)
+	}
+	if want := []string{"https://pypi.org/simple"}; !reflect.DeepEqual(req.ExtraIndexURLs, want) {
+		t.Errorf("ExtraIndexURLs = %v, want %v", req.ExtraIndexURLs, want)
+	}
+}
+
+func TestParseRequirementLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want Dependency
+		ok   bool
+	}{
+		{"requests", Dependency{Name: "requests"}, true},
+		{"requests == 2.31.0", Dependency{Name: "requests", Version: "==2.31.0"}, true},
+		{"zope.interface~=6.0", Dependency{Name: "zope.interface", Version: "~=6.0"}, true},
+		{"pkg[extra] ; os_name == 'nt'", Dependency{Name: "pkg", Extras: []string{"extra"}, Marker: "os_name == 'nt'"}, true},
+		{"pkg @ file:///opt/pkg", Dependency{Name: "pkg", Kind: SpecFile, Resolved: "file:///opt/pkg"}, true},
+		{"pkg @ https://example.com/pkg.whl", Dependency{Name: "pkg", Kind: SpecTarball, Resolved: "https://example.com/pkg.whl"}, true},
+		{"svn+https://svn.example.com/repo#egg=svnpkg", Dependency{Name: "svnpkg", Kind: SpecVCS, Resolved: "svn+https://svn.example.com/repo#egg=svnpkg"}, true},
+		{"./dist/pkg-1.0-py3-none-any.whl", Dependency{Name: "./dist/pkg-1.0-py3-none-any.whl", Kind: SpecFile, Resolved: "./dist/pkg-1.0-py3-none-any.whl"}, true},
+		{">=1.0", Dependency{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRequirementLine(tt.line, "")
+		if ok != tt.ok || (ok && !reflect.DeepEqual(got, tt.want)) {
+			t.Errorf("parseRequirementLine(%q) = %+v, %v, want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestSplitRequirementOption(t *testing.T) {
+	tests := []struct {
+		line, opt, value string
+	}{
+		{"-r base.txt", "-r", "base.txt"},
+		{"-rbase.txt", "-r", "base.txt"},
+		{"--requirement=base.txt", "--requirement", "base.txt"},
+		{"--index-url https://pypi.org/simple", "--index-url", "https://pypi.org/simple"},
+		{"--no-index", "--no-index", ""},
+		{"--pre", "--pre", ""},
+	}
+
+	for _, tt := range tests {
+		opt, value := splitRequirementOption(tt.line)
+		if opt != tt.opt || value != tt.value {
+			t.Errorf("splitRequirementOption(%q) = %q, %q, want %q, %q", tt.line, opt, value, tt.opt, tt.value)
+		}
+	}
+}
+
+func TestReadEmptyManifests(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty")
+	if err := os.WriteFile(empty, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing")
+
+	// An empty file has no requirements; only one that cannot be read is
+	// an error.
+	parsers := map[string]func(string) error{
+		"ParseRequirementsFile": func(path string) error { _, err := ParseRequirementsFile(path); return err },
+		"ExtractSetupCfg":       func(path string) error { _, err := ExtractSetupCfg(path); return err },
+		"ExtractToxIni":         func(path string) error { _, err := ExtractToxIni(path); return err },
+	}
+	for name, parse := range parsers {
+		if err := parse(empty); err != nil {
+			t.Errorf("%s(empty file) = %v, want no error", name, err)
+		}
+		if err := parse(missing); err == nil {
+			t.Errorf("%s(missing file) succeeded, want an error", name)
+		}
+	}
+}
@@ -0,0 +1,551 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// setupRequirementKeywords are the setup() arguments holding requirements.
+var setupRequirementKeywords = map[string]bool{
+	"install_requires": true,
+	"setup_requires":   true,
+	"tests_require":    true,
+	"extras_require":   true,
+}
+
+// ExtractSetupPy statically extracts the requirements passed to setup() in
+// a setup.py, without running it. Literal lists, tuples, dicts and strings
+// are understood, as are module-level variables holding them and list or
+// string concatenation. Arguments computed any other way (reading a file,
+// comprehensions, function calls) are reported with a warning.
+func ExtractSetupPy(path string) ([]Dependency, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &pyParser{tokens: tokenizePython(string(data)), vars: make(map[string]pyValue)}
+	var deps []Dependency
+	found := false
+
+	for p.pos < len(p.tokens) {
+		tok := p.next()
+
+		// Assignment statement: NAME = expr or NAME += expr
+		if tok.kind == pyName && tok.bol && p.peek().text == "=" {
+			p.next()
+			p.vars[tok.text] = p.expr()
+			continue
+		}
+		if tok.kind == pyName && tok.bol && p.peek().text == "+=" {
+			p.next()
+			p.vars[tok.text] = pyAdd(p.vars[tok.text], p.expr())
+			continue
+		}
+
+		if tok.kind != pyName || tok.text != "setup" || p.peek().text != "(" {
+			continue
+		}
+
+		found = true
+		p.next()
+		for p.pos < len(p.tokens) && p.peek().text != ")" {
+			if p.peek().kind == pyName && p.peekAt(1).text == "=" {
+				keyword := p.next().text
+				p.next()
+				value := p.expr()
+				if setupRequirementKeywords[keyword] {
+					deps = append(deps, setupValueDependencies(path, keyword, value)...)
+				}
+			} else {
+				// **kwargs or a positional argument.
+				if p.peek().text == "**" {
+					fmt.Printf("Warning: %s: setup() takes **kwargs, requirements may be computed dynamically and missed\n", path)
+				}
+				p.expr()
+			}
+			if p.peek().text == "," {
+				p.next()
+			}
+		}
+	}
+
+	if !found {
+		fmt.Printf("Warning: %s: no setup() call found\n", path)
+	}
+
+	sortDependencies(deps)
+	fmt.Printf("Parsed %s: %d requirements\n", path, len(deps))
+	return deps, nil
+}
+
+// setupValueDependencies converts the evaluated value of a setup() keyword
+// into dependencies, warning when it could not be resolved statically.
+func setupValueDependencies(path, keyword string, value pyValue) []Dependency {
+	if keyword != "extras_require" {
+		lines, ok := value.requirementLines()
+		if !ok {
+			fmt.Printf("Warning: %s: %s is computed dynamically and cannot be resolved statically\n", path, keyword)
+		}
+		return requirementDependencies(keyword, lines)
+	}
+
+	if value.kind != pyDict {
+		fmt.Printf("Warning: %s: extras_require is computed dynamically and cannot be resolved statically\n", path)
+		return nil
+	}
+
+	var deps []Dependency
+	for _, item := range value.items {
+		extra, _ := item.key.stringValue()
+		lines, ok := item.value.requirementLines()
+		if !ok {
+			fmt.Printf("Warning: %s: extras_require[%q] is computed dynamically and cannot be resolved statically\n", path, extra)
+		}
+		deps = append(deps, requirementDependencies("extras_require."+extra, lines)...)
+	}
+	return deps
+}
+
+func requirementDependencies(depType string, lines []string) []Dependency {
+	var deps []Dependency
+	for _, line := range lines {
+		line = stripRequirementComment(line)
+		if line == "" {
+			continue
+		}
+		if dep, ok := parseRequirementLine(line, depType); ok {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// ExtractSetupCfg returns the requirements in the [options] and
+// [options.extras_require] sections of a setup.cfg.
+func ExtractSetupCfg(path string) ([]Dependency, error) {
+	lines, err := readLines(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var deps []Dependency
+	var section, key string
+	var values []string
+	flush := func() {
+		if key == "" {
+			return
+		}
+		switch {
+		case section == "options" && setupRequirementKeywords[key] && key != "extras_require":
+			deps = append(deps, requirementDependencies(key, values)...)
+		case section == "options.extras_require":
+			deps = append(deps, requirementDependencies("extras_require."+key, values)...)
+		}
+		key, values = "", nil
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") {
+			continue
+		}
+
+		// Indented lines continue the previous value.
+		if line[0] == ' ' || line[0] == '\t' {
+			if key != "" {
+				values = append(values, trimmed)
+			}
+			continue
+		}
+
+		flush()
+		if strings.HasPrefix(trimmed, "[") {
+			section = strings.TrimSpace(strings.Trim(trimmed, "[]"))
+			continue
+		}
+
+		k, v, found := strings.Cut(trimmed, "=")
+		if !found {
+			k, v, found = strings.Cut(trimmed, ":")
+		}
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(k)
+		if v = strings.TrimSpace(v); v != "" {
+			if strings.HasPrefix(v, "file:") {
+				fmt.Printf("Warning: %s: %s is read from %s and cannot be resolved statically\n", path, key, strings.TrimSpace(strings.TrimPrefix(v, "file:")))
+			} else {
+				// A value on the key's own line is a list-semi:
+				// requirements separated by ";". Markers are only
+				// allowed in the dangling one-per-line form.
+				for _, item := range strings.Split(v, ";") {
+					if item = strings.TrimSpace(item); item != "" {
+						values = append(values, item)
+					}
+				}
+			}
+		}
+	}
+	flush()
+
+	sortDependencies(deps)
+	fmt.Printf("Parsed %s: %d requirements\n", path, len(deps))
+	return deps, nil
+}
+
+type pyTokenKind int
+
+const (
+	pyName pyTokenKind = iota
+	pyString
+	pyNumber
+	pyOp
+)
+
+type pyToken struct {
+	kind pyTokenKind
+	text string // for strings, the decoded value
+	// bol is set on the first token of a logical line, where a new
+	// statement starts.
+	bol bool
+}
+
+// tokenizePython splits Python source into names, string literals, numbers
+// and operators, dropping comments and whitespace. f-strings are kept as
+// their raw text, which is enough for requirement lists.
+func tokenizePython(src string) []pyToken {
+	var tokens []pyToken
+	depth := 0
+	bol := true
+	emit := func(tok pyToken) {
+		tok.bol = bol
+		bol = false
+		tokens = append(tokens, tok)
+	}
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '\\' && i+1 < len(src) && (src[i+1] == '\n' || src[i+1] == '\r'):
+			// Explicit line continuation.
+			i += 2
+			if i < len(src) && src[i-1] == '\r' && src[i] == '\n' {
+				i++
+			}
+		case c == '\n':
+			if depth == 0 {
+				bol = true
+			}
+			i++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\\' || c == '\f':
+			i++
+		case c == '"' || c == '\'' || (isStringPrefix(src[i:]) && stringStart(src[i:]) > 0):
+			start := stringStart(src[i:])
+			raw := strings.ContainsAny(strings.ToLower(src[i:i+start]), "r")
+			value, n := readPythonString(src[i+start:], raw)
+			emit(pyToken{kind: pyString, text: value})
+			i += start + n
+		case c == '_' || unicode.IsLetter(rune(c)):
+			j := i
+			for j < len(src) && (src[j] == '_' || unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+				j++
+			}
+			emit(pyToken{kind: pyName, text: src[i:j]})
+			i = j
+		case unicode.IsDigit(rune(c)):
+			j := i
+			for j < len(src) && (unicode.IsDigit(rune(src[j])) || src[j] == '.' || src[j] == '_' || unicode.IsLetter(rune(src[j]))) {
+				j++
+			}
+			emit(pyToken{kind: pyNumber, text: src[i:j]})
+			i = j
+		default:
+			op := src[i : i+1]
+			for _, two := range []string{"**", "+=", "==", "!=", "<=", ">=", "->", ":="} {
+				if strings.HasPrefix(src[i:], two) {
+					op = two
+					break
+				}
+			}
+			switch op {
+			case "(", "[", "{":
+				depth++
+			case ")", "]", "}":
+				if depth > 0 {
+					depth--
+				}
+			}
+			emit(pyToken{kind: pyOp, text: op})
+			i += len(op)
+		}
+	}
+	return tokens
+}
+
+func isStringPrefix(s string) bool {
+	switch c := unicode.ToLower(rune(s[0])); c {
+	case 'r', 'b', 'u', 'f':
+		return true
+	}
+	return false
+}
+
+// stringStart returns the offset of the opening quote of a string literal
+// with an optional prefix (r, b, u, f, rb...), or 0 if s is not one.
+func stringStart(s string) int {
+	for i := 0; i < len(s) && i < 3; i++ {
+		switch s[i] {
+		case '"', '\'':
+			return i
+		case 'r', 'R', 'b', 'B', 'u', 'U', 'f', 'F':
+			continue
+		}
+		return 0
+	}
+	return 0
+}
+
+// readPythonString reads a string literal starting at its opening quote and
+// returns its value and length. Only common escapes are decoded.
+func readPythonString(s string, raw bool) (string, int) {
+	quote := s[:1]
+	if strings.HasPrefix(s, strings.Repeat(quote, 3)) {
+		quote = strings.Repeat(quote, 3)
+	}
+
+	var b strings.Builder
+	i := len(quote)
+	for i < len(s) {
+		if strings.HasPrefix(s[i:], quote) {
+			return b.String(), i + len(quote)
+		}
+		if s[i] == '\\' && i+1 < len(s) {
+			if raw {
+				b.WriteString(s[i : i+2])
+			} else {
+				switch s[i+1] {
+				case 'n':
+					b.WriteByte('\n')
+				case 't':
+					b.WriteByte('\t')
+				case '\n':
+				default:
+					b.WriteByte(s[i+1])
+				}
+			}
+			i += 2
+			continue
+		}
+		if len(quote) == 1 && s[i] == '\n' {
+			break
+		}
+		b.WriteByte(s[i])
+		i++
+	}
+	return b.String(), i
+}
+
+type pyValueKind int
+
+const (
+	pyUnknown pyValueKind = iota
+	pyStr
+	pyList
+	pyDict
+)
+
+// pyValue is the statically known value of a Python expression.
+type pyValue struct {
+	kind  pyValueKind
+	str   string
+	list  []pyValue
+	items []pyDictItem
+}
+
+type pyDictItem struct {
+	key, value pyValue
+}
+
+func (v pyValue) stringValue() (string, bool) {
+	return v.str, v.kind == pyStr
+}
+
+// requirementLines flattens a string (one requirement per line) or a list of
+// strings. ok is false when any part of the value is unknown.
+func (v pyValue) requirementLines() ([]string, bool) {
+	switch v.kind {
+	case pyStr:
+		return strings.Split(v.str, "\n"), true
+	case pyList:
+		var lines []string
+		ok := true
+		for _, item := range v.list {
+			l, itemOK := item.requirementLines()
+			lines = append(lines, l...)
+			ok = ok && itemOK
+		}
+		return lines, ok
+	}
+	return nil, false
+}
+
+func pyAdd(a, b pyValue) pyValue {
+	switch {
+	case a.kind == pyStr && b.kind == pyStr:
+		return pyValue{kind: pyStr, str: a.str + b.str}
+	case a.kind == pyList && b.kind == pyList:
+		return pyValue{kind: pyList, list: append(append([]pyValue{}, a.list...), b.list...)}
+	}
+	return pyValue{}
+}
+
+// pyParser evaluates the literal subset of Python expressions.
+type pyParser struct {
+	tokens []pyToken
+	pos    int
+	vars   map[string]pyValue
+}
+
+func (p *pyParser) peek() pyToken { return p.peekAt(0) }
+
+func (p *pyParser) peekAt(n int) pyToken {
+	if p.pos+n < len(p.tokens) {
+		return p.tokens[p.pos+n]
+	}
+	return pyToken{kind: pyOp}
+}
+
+func (p *pyParser) next() pyToken {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+// expr parses one expression up to a top-level ",", ")", "]", "}" or ":".
+func (p *pyParser) expr() pyValue {
+	value := p.operand()
+	for p.peek().text == "+" {
+		p.next()
+		value = pyAdd(value, p.operand())
+	}
+
+	// Anything else (calls, attribute access, comprehensions, conditional
+	// expressions) makes the value unknown; skip the rest of it.
+	if !p.atExprEnd() {
+		value = pyValue{}
+		for !p.atExprEnd() {
+			p.skipToken()
+		}
+	}
+	return value
+}
+
+func (p *pyParser) atExprEnd() bool {
+	if p.pos >= len(p.tokens) {
+		return true
+	}
+	switch p.peek().text {
+	case ",", ")", "]", "}", ":":
+		return p.peek().kind == pyOp
+	}
+	return p.peek().bol
+}
+
+// skipToken skips a token, or a whole bracketed group.
+func (p *pyParser) skipToken() {
+	tok := p.next()
+	if tok.kind != pyOp {
+		return
+	}
+	closing := map[string]string{"(": ")", "[": "]", "{": "}"}[tok.text]
+	if closing == "" {
+		return
+	}
+	for p.pos < len(p.tokens) && p.peek().text != closing {
+		p.skipToken()
+	}
+	p.next()
+}
+
+func (p *pyParser) operand() pyValue {
+	tok := p.peek()
+	switch {
+	case tok.kind == pyString:
+		var b strings.Builder
+		for p.peek().kind == pyString {
+			b.WriteString(p.next().text)
+		}
+		return pyValue{kind: pyStr, str: b.String()}
+	case tok.kind == pyName:
+		p.next()
+		if v, ok := p.vars[tok.text]; ok && p.peek().text != "(" && p.peek().text != "." && p.peek().text != "[" {
+			return v
+		}
+		return pyValue{}
+	case tok.text == "[" || tok.text == "(":
+		closing := "]"
+		if tok.text == "(" {
+			closing = ")"
+		}
+		p.next()
+		list := pyValue{kind: pyList}
+		for p.pos < len(p.tokens) && p.peek().text != closing {
+			item := p.expr()
+			if p.peek().text == ":" || (p.peek().kind == pyName && p.peek().text == "for") {
+				return p.unknownUntil(closing)
+			}
+			list.list = append(list.list, item)
+			if p.peek().text == "," {
+				p.next()
+			} else if p.peek().text != closing {
+				return p.unknownUntil(closing)
+			}
+		}
+		p.next()
+		// A parenthesized single expression is the expression itself.
+		if closing == ")" && len(list.list) == 1 && p.tokens[p.pos-2].text != "," {
+			return list.list[0]
+		}
+		return list
+	case tok.text == "{":
+		p.next()
+		dict := pyValue{kind: pyDict}
+		for p.pos < len(p.tokens) && p.peek().text != "}" {
+			key := p.expr()
+			if p.peek().text != ":" {
+				return p.unknownUntil("}")
+			}
+			p.next()
+			value := p.expr()
+			dict.items = append(dict.items, pyDictItem{key: key, value: value})
+			if p.peek().text == "," {
+				p.next()
+			} else if p.peek().text != "}" {
+				return p.unknownUntil("}")
+			}
+		}
+		p.next()
+		return dict
+	}
+
+	p.skipToken()
+	return pyValue{}
+}
+
+// unknownUntil skips to the closing bracket of a construct that cannot be
+// evaluated, such as a comprehension.
+func (p *pyParser) unknownUntil(closing string) pyValue {
+	for p.pos < len(p.tokens) && p.peek().text != closing {
+		p.skipToken()
+	}
+	p.next()
+	return pyValue{}
+}
@@ -0,0 +1,143 @@
+package scanner
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExtractSetupPy(t *testing.T) {
+	tests := []struct {
+		dir  string
+		want []Dependency
+	}{
+		{
+			// Variables, += and list concatenation are followed; the
+			// comprehension in extras_require["all"] is skipped.
+			dir: "literal",
+			want: []Dependency{
+				{Name: "sphinx", Version: ">=7", Type: "extras_require.docs"},
+				{Name: "sphinx-rtd-theme", Type: "extras_require.docs"},
+				{Name: "PyYAML", Type: "extras_require.yaml"},
+				{Name: "attrs", Version: "==23.1.0", Type: "install_requires"},
+				{Name: "click", Type: "install_requires"},
+				{Name: "requests", Version: ">=2.28", Type: "install_requires"},
+				{Name: "rich", Type: "install_requires", Marker: "python_version >= '3.8'"},
+				{Name: "setuptools_scm", Version: ">=7", Type: "setup_requires"},
+				{Name: "wheel", Type: "setup_requires"},
+				{Name: "pytest", Version: ">=7", Type: "tests_require"},
+				{Name: "pytest-cov", Type: "tests_require"},
+			},
+		},
+		{
+			// Function calls, conditional expressions and **kwargs cannot be
+			// resolved statically.
+			dir:  "dynamic",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			deps, err := ExtractSetupPy(filepath.Join("testdata", "setup", tt.dir, "setup.py"))
+			if err != nil {
+				t.Fatalf("ExtractSetupPy: %v", err)
+			}
+			if !reflect.DeepEqual(deps, tt.want) {
+				t.Errorf("got  %+v\nwant %+v", deps, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractSetupCfg(t *testing.T) {
+	deps, err := ExtractSetupCfg(filepath.Join("testdata", "setup", "cfg", "setup.cfg"))
+	if err != nil {
+		t.Fatalf("ExtractSetupCfg: %v", err)
+	}
+
+	// One-line values are ";"-separated lists; dangling values hold one
+	// requirement per line, markers included. file: values are skipped.
+	want := []Dependency{
+		{Name: "sphinx", Version: ">=7", Type: "extras_require.docs"},
+		{Name: "PyYAML", Type: "extras_require.yaml"},
+		{Name: "ruamel.yaml", Type: "extras_require.yaml", Marker: `platform_python_implementation == "CPython"`},
+		{Name: "click", Type: "install_requires"},
+		{Name: "requests", Version: ">=2.28", Type: "install_requires"},
+		{Name: "setuptools_scm", Version: ">=7", Type: "setup_requires"},
+		{Name: "wheel", Type: "setup_requires", Marker: `python_version < "3.12"`},
+	}
+	if !reflect.DeepEqual(deps, want) {
+		t.Errorf("got  %+v\nwant %+v", deps, want)
+	}
+}
+
+func TestTokenizePython(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []pyToken
+	}{
+		{
+			`x = ["a", 'b']  # comment`,
+			[]pyToken{{pyName, "x", true}, {pyOp, "=", false}, {pyOp, "[", false}, {pyString, "a", false}, {pyOp, ",", false}, {pyString, "b", false}, {pyOp, "]", false}},
+		},
+		{
+			"r'a\\b' b\"c\" f'{x}' rb'd'",
+			[]pyToken{{pyString, `a\b`, true}, {pyString, "c", false}, {pyString, "{x}", false}, {pyString, "d", false}},
+		},
+		{
+			"'''one\ntwo''' \"esc\\\"aped\\n\"",
+			[]pyToken{{pyString, "one\ntwo", true}, {pyString, "esc\"aped\n", false}},
+		},
+		{
+			// Newlines inside brackets and after a backslash do not start a
+			// new statement.
+			"a = [\n1,\n]\nb = 2 + \\\n 3\nc += 1",
+			[]pyToken{
+				{pyName, "a", true}, {pyOp, "=", false}, {pyOp, "[", false}, {pyNumber, "1", false}, {pyOp, ",", false}, {pyOp, "]", false},
+				{pyName, "b", true}, {pyOp, "=", false}, {pyNumber, "2", false}, {pyOp, "+", false}, {pyNumber, "3", false},
+				{pyName, "c", true}, {pyOp, "+=", false}, {pyNumber, "1", false},
+			},
+		},
+		{
+			"f(**kw)",
+			[]pyToken{{pyName, "f", true}, {pyOp, "(", false}, {pyOp, "**", false}, {pyName, "kw", false}, {pyOp, ")", false}},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tokenizePython(tt.src); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("tokenizePython(%q):\ngot  %v\nwant %v", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestPyParserRequirementLines(t *testing.T) {
+	tests := []struct {
+		expr  string
+		lines []string
+		ok    bool
+	}{
+		{`["a", "b"]`, []string{"a", "b"}, true},
+		{`("a",)`, []string{"a"}, true},
+		{`("a")`, []string{"a"}, true},
+		{`"a" "b"`, []string{"ab"}, true},
+		{"\"a\\nb\"", []string{"a", "b"}, true},
+		{`["a"] + ["b"] + BASE`, []string{"a", "b", "base"}, true},
+		{`["a", ["b", "c"]]`, []string{"a", "b", "c"}, true},
+		{`["a", other()]`, []string{"a"}, false},
+		{`[r for r in BASE]`, nil, false},
+		{`open("requirements.txt").read().splitlines()`, nil, false},
+		{`BASE if x else []`, nil, false},
+		{`UNDEFINED`, nil, false},
+		{`{"a": ["b"]}`, nil, false},
+	}
+
+	for _, tt := range tests {
+		p := &pyParser{tokens: tokenizePython(tt.expr), vars: map[string]pyValue{"BASE": {kind: pyList, list: []pyValue{{kind: pyStr, str: "base"}}}}}
+		lines, ok := p.expr().requirementLines()
+		if ok != tt.ok || !reflect.DeepEqual(lines, tt.lines) {
+			t.Errorf("%s: got %q, %v, want %q, %v", tt.expr, lines, ok, tt.lines, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+import os
+from setuptools import setup
+
+def read_requirements(name):
+    with open(os.path.join(os.path.dirname(__file__), name)) as f:
+        return f.read().splitlines()
+
+extras = {"dev": ["black"]}
+
+setup(
+    name="dynamic",
+    install_requires=read_requirements("requirements.txt"),
+    extras_require=extras if os.name != "nt" else {},
+    **{"tests_require": ["nose"]}
+)
@@ -0,0 +1,26 @@
+#!/usr/bin/env python
+from setuptools import setup, find_packages
+
+BASE = [
+    "requests>=2.28",  # HTTP
+    'click',
+]
+BASE += ["rich ; python_version >= '3.8'"]
+TESTS = ("pytest>=7", "pytest-cov")
+
+setup(
+    name="example",
+    version="1.0",
+    packages=find_packages(exclude=["tests"]),
+    install_requires=BASE + ["attrs==23.1.0"],
+    setup_requires="""
+        setuptools_scm>=7
+        wheel
+    """,
+    tests_require=TESTS,
+    extras_require={
+        "docs": ["sphinx>=7", r"sphinx-rtd-theme"],
+        'yaml': "PyYAML",
+        "all": [req for req in BASE],
+    },
+)
@@ -0,0 +1,170 @@
+package version
+
+import (
+	"regexp"
+	"strings"
+)
+
+// composerOr splits a Composer constraint into its alternatives.
+var composerOr = regexp.MustCompile(`\s*\|\|?\s*`)
+
+// composerModifier matches a version with a stability modifier, spelled any
+// of the ways Composer accepts: "1.0.0-beta.1", "1.0.0-b1", "1.0.0BETA1",
+// "2.1-rc.2", "1.0-p1".
+var composerModifier = regexp.MustCompile(`(?i)^(v?\d+(?:\.(?:\d+|[x*]))*)[._-]?(stable|beta|b|rc|alpha|a|patch|pl|p)((?:[.-]?\d+)*)$`)
+
+// composerStabilities maps the stability modifiers to the names Composer
+// normalizes them to. Patch releases come after their release; they are
+// compared as the release itself.
+var composerStabilities = map[string]string{
+	"alpha": "alpha", "a": "alpha",
+	"beta": "beta", "b": "beta",
+	"rc":     "RC",
+	"stable": "", "patch": "", "pl": "", "p": "",
+}
+
+// normalizeComposerVersion rewrites a version's stability modifier the way
+// Composer normalizes it ("1.0.0-beta.1", "1.0.0-b1" and "1.0.0-BETA1" are
+// all beta 1), with the number as a separate prerelease identifier so that
+// beta10 sorts after beta2.
+func normalizeComposerVersion(v string) string {
+	m := composerModifier.FindStringSubmatch(strings.TrimSpace(v))
+	if m == nil {
+		return v
+	}
+
+	stability := composerStabilities[strings.ToLower(m[2])]
+	if stability == "" {
+		return m[1]
+	}
+	parts := append([]string{stability}, strings.FieldsFunc(m[3], func(r rune) bool { return r == '.' || r == '-' })...)
+	return m[1] + "-" + strings.Join(parts, ".")
+}
+
+// composerStabilityRanks orders the stabilities a version can have, from
+// least to most stable.
+var composerStabilityRanks = map[string]int{"dev": 0, "alpha": 1, "beta": 2, "rc": 3, "stable": 4}
+
+// composerStability returns the stability rank of a normalized version's
+// prerelease tag. Tags that are not a stability modifier are dev versions.
+func composerStability(pre []string) int {
+	if len(pre) == 0 {
+		return composerStabilityRanks["stable"]
+	}
+	return composerStabilityRanks[strings.ToLower(pre[0])]
+}
+
+// ComposerSatisfiable reports whether any of the published versions satisfies
+// a Composer constraint ("^1.2", "~1.2", "1.0.*", ">=1.0 <2.0 || ^3",
+// "dev-main"). Stability modifiers are compared the way Composer normalizes
+// them, and prereleases only count when a stability flag ("^2.0@RC") or a
+// prerelease in the constraint itself ("2.0.0-beta1") allows them. ok is
+// false when the constraint cannot be evaluated, such as "self.version".
+func ComposerSatisfiable(constraint string, published []string) (satisfied, ok bool) {
+	var sets [][]comparator
+	minStability := composerStabilityRanks["stable"]
+	for _, alt := range composerOr.Split(strings.TrimSpace(constraint), -1) {
+		// Branches ("dev-main", "2.x-dev") only match themselves; a
+		// "#ref" suffix pins a commit on the branch.
+		branch, _, _ := strings.Cut(alt, "#")
+		branch, _, _ = strings.Cut(branch, "@")
+		if strings.HasPrefix(branch, "dev-") || strings.HasSuffix(branch, "-dev") {
+			for _, v := range published {
+				if strings.EqualFold(strings.TrimPrefix(v, "v"), strings.TrimPrefix(branch, "v")) {
+					return true, true
+				}
+			}
+			continue
+		}
+
+		set, stability, ok := parseComposerConstraint(alt)
+		if !ok {
+			return false, false
+		}
+		sets = append(sets, set)
+		minStability = min(minStability, stability)
+	}
+
+	var allowed []string
+	for _, v := range published {
+		v = normalizeComposerVersion(v)
+		if sv, ok := parseSemver(v); ok && composerStability(sv.pre) >= minStability {
+			allowed = append(allowed, v)
+		}
+	}
+	return satisfiable(sets, allowed), true
+}
+
+// parseComposerConstraint parses one alternative of a Composer constraint
+// and returns the lowest stability it allows.
+func parseComposerConstraint(s string) ([]comparator, int, bool) {
+	stability := composerStabilityRanks["stable"]
+	var set []comparator
+	if lo, hi, found := strings.Cut(s, " - "); found {
+		var ok bool
+		if set, ok = hyphenRange(lo, hi); !ok {
+			return nil, 0, false
+		}
+		return composerDevBounds(set), stability, true
+	}
+
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+	for _, f := range joinOperators(fields) {
+		f, flag, hasFlag := strings.Cut(f, "@")
+		if hasFlag {
+			rank, ok := composerStabilityRanks[strings.ToLower(flag)]
+			if !ok {
+				return nil, 0, false
+			}
+			stability = min(stability, rank)
+		}
+		if f == "" {
+			continue
+		}
+		op, v := splitOperator(f)
+		p, ok := parsePartial(normalizeComposerVersion(v))
+		if !ok {
+			return nil, 0, false
+		}
+		if p.n == 0 {
+			continue
+		}
+		if len(p.pre) > 0 {
+			stability = min(stability, composerStability(p.pre))
+		}
+
+		switch op {
+		case "", "=", "==":
+			// Unlike npm, "1.2" is exactly 1.2.0; only "1.2.*" is a range.
+			if p.wild {
+				set = append(set, wildcardRange(p)...)
+			} else {
+				set = append(set, comparator{"=", p.semver})
+			}
+		case "!=", "<>":
+			set = append(set, comparator{"!=", p.semver})
+		case "^":
+			set = append(set, caretRange(p)...)
+		case "~":
+			// The last given component may change: ~1.2 is <2.0, ~1.2.3
+			// is <1.3.0.
+			set = append(set, comparator{">=", p.semver}, comparator{"<", bump(p, max(p.n-1, 1))})
+		default:
+			set = append(set, comparator{op, p.semver})
+		}
+	}
+	return composerDevBounds(set), stability, true
+}
+
+// composerDevBounds moves the >= and < bounds given without a prerelease
+// below every prerelease of their version, as Composer's "-dev" suffix does:
+// ^2.0 is >=2.0.0-dev <3.0.0-dev, so it covers 2.0.0-RC1 when prereleases
+// are allowed.
+func composerDevBounds(set []comparator) []comparator {
+	for i, c := range set {
+		if (c.op == ">=" || c.op == "<") && len(c.v.pre) == 0 {
+			set[i].v.pre = []string{"0"}
+		}
+	}
+	return set
+}
@@ -0,0 +1,101 @@
+package version
+
+import "testing"
+
+func TestComposerSatisfiable(t *testing.T) {
+	runSatisfiable(t, ComposerSatisfiable, []satisfiableTest{
+		// Exact versions and wildcards.
+		{"1.2.3", []string{"1.2.3"}, true, true},
+		{"v1.0.0", []string{"1.0.0"}, true, true},
+		{"=1.2.3", []string{"v1.2.3"}, true, true},
+		{"1.2", []string{"1.2.5"}, false, true},
+		{"1.2", []string{"1.2.0"}, true, true},
+		{"1.2.*", []string{"1.2.9"}, true, true},
+		{"1.2.*", []string{"1.3.0"}, false, true},
+		{"1.2.3.*", []string{"1.2.3.5"}, true, true},
+		{"1.2.3.*", []string{"1.2.4"}, false, true},
+		{"*", []string{"0.1.0"}, true, true},
+
+		// Caret and tilde.
+		{"^1.2", []string{"1.9.0"}, true, true},
+		{"^1.2", []string{"2.0.0"}, false, true},
+		{"^0.3", []string{"0.3.9"}, true, true},
+		{"^0.3", []string{"0.4.0"}, false, true},
+		{"~1.2", []string{"1.9.0"}, true, true},
+		{"~1.2", []string{"2.0.0"}, false, true},
+		{"~1.2.3", []string{"1.2.9"}, true, true},
+		{"~1.2.3", []string{"1.3.0"}, false, true},
+
+		// Comparisons, ranges and alternatives.
+		{">=1.0 <2.0", []string{"1.5.0"}, true, true},
+		{">=1.0,<2.0", []string{"2.0.0"}, false, true},
+		{">= 1.0", []string{"1.0.0"}, true, true},
+		{"!=1.0.0", []string{"1.0.0"}, false, true},
+		{"<>1.0.0", []string{"1.0.1"}, true, true},
+		{"1.0 - 2.0", []string{"2.0.5"}, true, true},
+		{"1.0 - 2.0", []string{"2.1.0"}, false, true},
+		{"^1.0 || ^3.0", []string{"3.2.0"}, true, true},
+		{"^1.0 | ^3.0", []string{"2.0.0"}, false, true},
+
+		// Stability flags, branches and modifiers.
+		{"^1.2@dev", []string{"1.3.0"}, true, true},
+		{"dev-main", []string{"dev-main"}, true, true},
+		{"dev-main#abc123", []string{"dev-main"}, true, true},
+		{"dev-feature", []string{"dev-main"}, false, true},
+		{"2.x-dev", []string{"2.x-dev"}, true, true},
+		{"1.0.0-beta1", []string{"1.0.0-beta.1"}, true, true},
+		{"1.0.0-beta.1", []string{"v1.0.0-BETA1"}, true, true},
+		{"1.0.0-b1", []string{"1.0.0-beta1"}, true, true},
+		{"1.0.0-RC1", []string{"1.0.0-rc.1"}, true, true},
+		{">=1.0.0-beta2", []string{"1.0.0-beta10"}, true, true},
+		{"1.0.0-alpha1", []string{"1.0.0-beta1"}, false, true},
+		{"^1.0", []string{"1.0.0-p1"}, true, true},
+
+		// Prereleases need a stability flag or a prerelease constraint.
+		{"^2.0", []string{"2.0.0-RC1"}, false, true},
+		{"^2.0@RC", []string{"2.0.0-RC1"}, true, true},
+		{"^3.0@beta", []string{"v3.0.0-beta2"}, true, true},
+		{"^3.0@RC", []string{"v3.0.0-beta2"}, false, true},
+		{"~1.2@alpha", []string{"1.2.0-alpha3"}, true, true},
+		{"1.2.*@beta", []string{"1.2.0-b1"}, true, true},
+		{">=2.0@dev", []string{"2.0.0-alpha1"}, true, true},
+		{"^1.0 || ^2.0@RC", []string{"2.0.0-rc.2"}, true, true},
+		{"<1.0.0", []string{"1.0.0-RC1"}, false, true},
+		{"<1.0.0@RC", []string{"1.0.0-RC1"}, false, true},
+		{"<1.0.0@RC", []string{"0.9.0-RC1"}, true, true},
+		{"2.0.0-beta1", []string{"2.0.0-beta1"}, true, true},
+		{">=2.0.0-beta1", []string{"2.0.0-RC1"}, true, true},
+		{"dev-main@dev", []string{"dev-main"}, true, true},
+
+		// Not evaluated.
+		{"self.version", []string{"1.0.0"}, false, false},
+		{"^1.0 || self.version", []string{"1.0.0"}, false, false},
+		{"^1.0@unstable", []string{"1.0.0"}, false, false},
+	})
+}
+
+func TestNormalizeComposerVersion(t *testing.T) {
+	tests := []struct {
+		version, want string
+	}{
+		{"1.0.0", "1.0.0"},
+		{"1.0.0-beta.1", "1.0.0-beta.1"},
+		{"1.0.0-beta1", "1.0.0-beta.1"},
+		{"1.0.0-b1", "1.0.0-beta.1"},
+		{"1.0.0-BETA1", "1.0.0-beta.1"},
+		{"1.0.0beta1", "1.0.0-beta.1"},
+		{"v2.1-rc.2", "v2.1-RC.2"},
+		{"1.0.0-a", "1.0.0-alpha"},
+		{"1.0.0-alpha.1.2", "1.0.0-alpha.1.2"},
+		{"1.0.0-stable", "1.0.0"},
+		{"1.0.0-pl3", "1.0.0"},
+		{"dev-main", "dev-main"},
+		{"1.0.x-dev", "1.0.x-dev"},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeComposerVersion(tt.version); got != tt.want {
+			t.Errorf("normalizeComposerVersion(%q) = %q, want %q", tt.version, got, tt.want)
+		}
+	}
+}
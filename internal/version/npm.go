@@ -0,0 +1,70 @@
+package version
+
+import "strings"
+
+// NPMSatisfiable reports whether any of the published versions satisfies an
+// npm semver range ("^1.2.3", "~1.2", ">=1 <2 || 3.x", "1.2.3 - 2"). ok is
+// false when the range cannot be evaluated, such as a dist-tag.
+func NPMSatisfiable(constraint string, published []string) (satisfied, ok bool) {
+	var sets [][]comparator
+	for _, alt := range strings.Split(constraint, "||") {
+		set, ok := parseNPMComparatorSet(strings.TrimSpace(alt))
+		if !ok {
+			return false, false
+		}
+		sets = append(sets, set)
+	}
+	return satisfiable(sets, published), true
+}
+
+func parseNPMComparatorSet(s string) ([]comparator, bool) {
+	if lo, hi, found := strings.Cut(s, " - "); found {
+		return hyphenRange(lo, hi)
+	}
+
+	var set []comparator
+	for _, f := range joinOperators(strings.Fields(s)) {
+		op, v := splitOperator(f)
+		p, ok := parsePartial(v)
+		if !ok {
+			return nil, false
+		}
+		if p.n == 0 {
+			// "*", ">=*" and the like match everything.
+			continue
+		}
+
+		switch op {
+		case "", "=":
+			set = append(set, wildcardRange(p)...)
+		case "^":
+			set = append(set, caretRange(p)...)
+		case "~":
+			// ~1.2.3 and ~1.2 allow patch changes, ~1 minor ones.
+			set = append(set, comparator{">=", p.semver}, comparator{"<", bump(p, min(p.n, 2))})
+		case ">=":
+			set = append(set, comparator{">=", p.semver})
+		case "<":
+			if p.n < 3 {
+				// <2 is <2.0.0-0, below the 2.0.0 prereleases too.
+				p.pre = []string{"0"}
+			}
+			set = append(set, comparator{"<", p.semver})
+		case ">":
+			if p.n >= 3 {
+				set = append(set, comparator{">", p.semver})
+			} else {
+				set = append(set, comparator{">=", bump(p, p.n)})
+			}
+		case "<=":
+			if p.n >= 3 {
+				set = append(set, comparator{"<=", p.semver})
+			} else {
+				set = append(set, comparator{"<", bump(p, p.n)})
+			}
+		default:
+			return nil, false
+		}
+	}
+	return set, true
+}
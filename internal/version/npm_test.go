@@ -0,0 +1,79 @@
+package version
+
+import "testing"
+
+// satisfiableTest is a constraint checked against published versions.
+type satisfiableTest struct {
+	constraint string
+	published  []string
+	satisfied  bool
+	ok         bool
+}
+
+func runSatisfiable(t *testing.T, fn func(string, []string) (bool, bool), tests []satisfiableTest) {
+	t.Helper()
+	for _, tt := range tests {
+		satisfied, ok := fn(tt.constraint, tt.published)
+		if satisfied != tt.satisfied || ok != tt.ok {
+			t.Errorf("%q against %v = %v, %v, want %v, %v", tt.constraint, tt.published, satisfied, ok, tt.satisfied, tt.ok)
+		}
+	}
+}
+
+func TestNPMSatisfiable(t *testing.T) {
+	runSatisfiable(t, NPMSatisfiable, []satisfiableTest{
+		// Exact versions and partial versions as ranges.
+		{"1.2.3", []string{"1.2.3"}, true, true},
+		{"1.2.3", []string{"1.2.4"}, false, true},
+		{"=1.2.3", []string{"1.2.3"}, true, true},
+		{"v1.2.3", []string{"1.2.3"}, true, true},
+		{"1.2.3", []string{"not-a-version", "1.2.3"}, true, true},
+		{"1.2", []string{"1.2.9"}, true, true},
+		{"1.2", []string{"1.3.0"}, false, true},
+		{"1.x", []string{"1.9.0"}, true, true},
+		{"1.2.x", []string{"1.3.0"}, false, true},
+		{"*", []string{"0.0.1"}, true, true},
+		{"", []string{"0.0.1"}, true, true},
+
+		// Caret and tilde.
+		{"^1.2.3", []string{"1.9.9"}, true, true},
+		{"^1.2.3", []string{"2.0.0"}, false, true},
+		{"^0.2.3", []string{"0.2.9"}, true, true},
+		{"^0.2.3", []string{"0.3.0"}, false, true},
+		{"^0.0.3", []string{"0.0.4"}, false, true},
+		{"^0", []string{"0.9.0"}, true, true},
+		{"~1.2.3", []string{"1.2.9"}, true, true},
+		{"~1.2.3", []string{"1.3.0"}, false, true},
+		{"~1", []string{"1.9.0"}, true, true},
+		{"~1", []string{"2.0.0"}, false, true},
+
+		// Comparison operators, with partial versions.
+		{">=1.2.0", []string{"1.2.0"}, true, true},
+		{">1.2.0", []string{"1.2.0"}, false, true},
+		{">1.2", []string{"1.2.9"}, false, true},
+		{">1.2", []string{"1.3.0"}, true, true},
+		{"<=1.2", []string{"1.2.9"}, true, true},
+		{"<=1.2.3", []string{"1.2.4"}, false, true},
+		{"<2", []string{"2.0.0-rc.1"}, false, true},
+		{">= 1.2.0 < 2", []string{"1.5.0"}, true, true},
+
+		// Hyphen ranges and alternatives.
+		{"1.2.3 - 2.3", []string{"2.3.9"}, true, true},
+		{"1.2.3 - 2.3", []string{"2.4.0"}, false, true},
+		{"1.2.3 - 2.3.4", []string{"2.3.4"}, true, true},
+		{"<1.0.0 || >=3", []string{"3.1.0"}, true, true},
+		{"<1.0.0 || >=3", []string{"2.0.0"}, false, true},
+
+		// Prereleases.
+		{"1.0.0-beta.2", []string{"1.0.0-beta.10"}, false, true},
+		{">=1.0.0-beta.2", []string{"1.0.0-beta.10"}, true, true},
+		{">1.0.0-alpha", []string{"1.0.0-alpha.1"}, true, true},
+		{"<1.0.0", []string{"1.0.0-rc.1"}, true, true},
+
+		// Not evaluated.
+		{"latest", []string{"1.0.0"}, false, false},
+		{"^1.0.0 || next", []string{"1.0.0"}, false, false},
+		{"~>1.2", []string{"1.2.0"}, false, false},
+		{"1.2.3.4.5", []string{"1.2.3"}, false, false},
+	})
+}
@@ -0,0 +1,247 @@
+package version
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pep440Pattern matches a PEP 440 version in any of its accepted spellings.
+var pep440Pattern = regexp.MustCompile(`(?i)^v?(?:(\d+)!)?(\d+(?:\.\d+)*)` +
+	`(?:[-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?(\d+)?)?` +
+	`(?:-(\d+)|[-_.]?(post|rev|r)[-_.]?(\d+)?)?` +
+	`(?:[-_.]?(dev)[-_.]?(\d+)?)?` +
+	`(?:\+[a-z0-9]+(?:[-_.][a-z0-9]+)*)?$`)
+
+// pep440Version is a parsed PEP 440 version. Local labels are ignored.
+type pep440Version struct {
+	epoch   int
+	release []int
+	// key orders the pre, post and dev parts the way PEP 440 does:
+	// dev < pre < final < post.
+	key [7]int
+}
+
+var preReleasePhases = map[string]int{"a": 0, "alpha": 0, "b": 1, "beta": 1, "c": 2, "rc": 2, "pre": 2, "preview": 2}
+
+func parsePEP440(s string) (pep440Version, bool) {
+	m := pep440Pattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return pep440Version{}, false
+	}
+
+	var v pep440Version
+	v.epoch, _ = strconv.Atoi(m[1])
+	for _, part := range strings.Split(m[2], ".") {
+		n, _ := strconv.Atoi(part)
+		v.release = append(v.release, n)
+	}
+
+	num := func(s string) int {
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+	hasPre := m[3] != ""
+	hasPost := m[5] != "" || m[6] != ""
+	hasDev := m[8] != ""
+
+	// Pre: a dev release of a final version sorts before its prereleases,
+	// and a final version after them.
+	switch {
+	case hasPre:
+		v.key[0], v.key[1], v.key[2] = 0, preReleasePhases[strings.ToLower(m[3])], num(m[4])
+	case !hasPost && hasDev:
+		v.key[0] = -1
+	default:
+		v.key[0] = 1
+	}
+	// Post: no post release sorts first.
+	v.key[3] = -1
+	if hasPost {
+		v.key[3], v.key[4] = 0, num(m[5]+m[7])
+	}
+	// Dev: no dev release sorts last.
+	v.key[5] = 1
+	if hasDev {
+		v.key[5], v.key[6] = 0, num(m[9])
+	}
+	return v, true
+}
+
+func comparePEP440(a, b pep440Version) int {
+	if c := compareInt(a.epoch, b.epoch); c != 0 {
+		return c
+	}
+	if c := compareRelease(a.release, b.release); c != 0 {
+		return c
+	}
+	for i := range a.key {
+		if c := compareInt(a.key[i], b.key[i]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// compareRelease compares release segments, padding the shorter with zeros.
+func compareRelease(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if c := compareInt(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// pep440Clause is one specifier clause such as ">=1.2" or "==1.4.*".
+type pep440Clause struct {
+	op     string
+	v      pep440Version
+	raw    string
+	prefix bool
+}
+
+func (c pep440Clause) matches(raw string, v pep440Version) bool {
+	if c.op == "===" {
+		return strings.EqualFold(strings.TrimSpace(raw), c.raw)
+	}
+	if c.prefix {
+		// "==1.4.*" matches every version whose release starts with 1.4.
+		match := v.epoch == c.v.epoch
+		for i, n := range c.v.release {
+			var got int
+			if i < len(v.release) {
+				got = v.release[i]
+			}
+			match = match && got == n
+		}
+		return match == (c.op == "==")
+	}
+
+	cmp := comparePEP440(v, c.v)
+	switch c.op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case "<":
+		// "<2" excludes the prereleases of 2 unless it names one itself.
+		return cmp < 0 && !(v.isPrerelease() && !c.v.isPrerelease() && v.epoch == c.v.epoch && compareRelease(v.release, c.v.release) == 0)
+	}
+	return false
+}
+
+func (v pep440Version) isPrerelease() bool {
+	return v.key[0] != 1 || v.key[5] == 0
+}
+
+// PEP440Satisfiable reports whether any of the published versions satisfies
+// a PEP 440 specifier (">=1.2,<2", "~=1.4.2", "==1.4.*"). Poetry's caret and
+// tilde constraints, bare versions and "||" alternatives are understood as
+// well. ok is false when the specifier cannot be evaluated.
+func PEP440Satisfiable(constraint string, published []string) (satisfied, ok bool) {
+	var sets [][]pep440Clause
+	for _, alt := range strings.Split(constraint, "||") {
+		var set []pep440Clause
+		for _, clause := range strings.Split(alt, ",") {
+			clauses, ok := parsePEP440Clause(strings.TrimSpace(clause))
+			if !ok {
+				return false, false
+			}
+			set = append(set, clauses...)
+		}
+		sets = append(sets, set)
+	}
+
+	for _, raw := range published {
+		v, ok := parsePEP440(raw)
+		if !ok {
+			continue
+		}
+		for _, set := range sets {
+			all := true
+			for _, c := range set {
+				all = all && c.matches(raw, v)
+			}
+			if all {
+				return true, true
+			}
+		}
+	}
+	return false, true
+}
+
+func parsePEP440Clause(s string) ([]pep440Clause, bool) {
+	if s == "" || s == "*" {
+		return nil, true
+	}
+
+	op := ""
+	for _, candidate := range []string{"===", "~=", "==", "!=", ">=", "<=", ">", "<", "^", "~", "="} {
+		if rest, found := strings.CutPrefix(s, candidate); found {
+			op, s = candidate, strings.TrimSpace(rest)
+			break
+		}
+	}
+	if op == "===" {
+		return []pep440Clause{{op: op, raw: s}}, true
+	}
+	if op == "" || op == "=" {
+		// Poetry: a bare version is an exact pin.
+		op = "=="
+	}
+
+	prefix := false
+	if trimmed, found := strings.CutSuffix(s, ".*"); found {
+		if op != "==" && op != "!=" {
+			return nil, false
+		}
+		s, prefix = trimmed, true
+	}
+	v, ok := parsePEP440(s)
+	if !ok {
+		return nil, false
+	}
+
+	// upper returns the release with component n (1-based) incremented and
+	// the rest dropped: upper(1.4.2, 2) is 1.5.
+	upper := func(n int) pep440Version {
+		release := append([]int{}, v.release[:n]...)
+		release[n-1]++
+		u := pep440Version{epoch: v.epoch, release: release}
+		// The first dev release of that version, below its prereleases.
+		u.key = [7]int{-1, 0, 0, -1, 0, 0, 0}
+		return u
+	}
+
+	switch op {
+	case "~=":
+		if len(v.release) < 2 {
+			return nil, false
+		}
+		return []pep440Clause{{op: ">=", v: v}, {op: "<", v: upper(len(v.release) - 1)}}, true
+	case "^":
+		n := 1
+		for n < len(v.release) && v.release[n-1] == 0 {
+			n++
+		}
+		return []pep440Clause{{op: ">=", v: v}, {op: "<", v: upper(n)}}, true
+	case "~":
+		// Poetry: ~1.2.3 and ~1.2 allow patch changes, ~1 minor ones.
+		return []pep440Clause{{op: ">=", v: v}, {op: "<", v: upper(min(len(v.release), 2))}}, true
+	}
+	return []pep440Clause{{op: op, v: v, prefix: prefix}}, true
+}
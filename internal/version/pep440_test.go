@@ -0,0 +1,67 @@
+package version
+
+import "testing"
+
+func TestPEP440Satisfiable(t *testing.T) {
+	runSatisfiable(t, PEP440Satisfiable, []satisfiableTest{
+		// Version matching, with zero padding and prefixes.
+		{"==1.4.2", []string{"1.4.2"}, true, true},
+		{"==1.4", []string{"1.4.0"}, true, true},
+		{"==1.4.2", []string{"1.4.3"}, false, true},
+		{"==1.4.*", []string{"1.4.9"}, true, true},
+		{"==1.4.*", []string{"1.5.0"}, false, true},
+		{"!=1.4.*", []string{"1.4.1"}, false, true},
+		{"!=1.4.*", []string{"1.5"}, true, true},
+		{"!=1.0", []string{"1.0"}, false, true},
+		{"===1.0", []string{"1.0"}, true, true},
+		{"===1.0", []string{"1.0.0"}, false, true},
+
+		// Compatible release.
+		{"~=1.4.2", []string{"1.4.9"}, true, true},
+		{"~=1.4.2", []string{"1.5.0"}, false, true},
+		{"~=1.4", []string{"1.9"}, true, true},
+		{"~=1.4", []string{"2.0"}, false, true},
+
+		// Ordered comparison.
+		{">=1.0,<2.0", []string{"1.5"}, true, true},
+		{">=1.0, <2.0", []string{"2.0"}, false, true},
+		{"<=1.0", []string{"1.0"}, true, true},
+		{">1.0", []string{"1.0.post1"}, true, true},
+		{"<0.5 || >=2", []string{"2.1"}, true, true},
+
+		// Pre, post and dev releases.
+		{"<2.0", []string{"2.0rc1"}, false, true},
+		{"<2.0rc2", []string{"2.0rc1"}, true, true},
+		{">=1.0a1", []string{"1.0b2"}, true, true},
+		{"<1.0a1", []string{"1.0.dev1"}, true, true},
+		{">=1.0", []string{"1.0.dev1"}, false, true},
+		{"==1.0a1", []string{"1.0-alpha-1"}, true, true},
+		{"==1.0rc1", []string{"1.0c1"}, true, true},
+		{"==1.0.post1", []string{"1.0-1"}, true, true},
+
+		// Epochs and local versions.
+		{"==1!1.0", []string{"1.0"}, false, true},
+		{">=2.0", []string{"1!1.0"}, true, true},
+		{"==1!1.*", []string{"1!1.5"}, true, true},
+		{"==1.0", []string{"1.0+local.7"}, true, true},
+
+		// Poetry caret, tilde and bare versions.
+		{"^1.2.3", []string{"1.9"}, true, true},
+		{"^1.2.3", []string{"2.0"}, false, true},
+		{"^0.2.3", []string{"0.3.0"}, false, true},
+		{"^0.0.3", []string{"0.0.4"}, false, true},
+		{"~1.2.3", []string{"1.2.9"}, true, true},
+		{"~1.2.3", []string{"1.3"}, false, true},
+		{"~1", []string{"1.9"}, true, true},
+		{"~1", []string{"2.0"}, false, true},
+		{"1.2.3", []string{"1.2.3"}, true, true},
+		{"1.2.3", []string{"1.2.4"}, false, true},
+		{"*", []string{"0.1"}, true, true},
+
+		// Not evaluated.
+		{"~=1", []string{"1.0"}, false, false},
+		{">=1.*", []string{"1.0"}, false, false},
+		{"latest", []string{"1.0"}, false, false},
+		{">=1.0,nope", []string{"1.0"}, false, false},
+	})
+}
@@ -0,0 +1,257 @@
+// Package version evaluates the version constraints of each ecosystem (npm
+// semver ranges, PEP 440 specifiers, Composer constraints) against the
+// versions published on a registry.
+package version
+
+import (
+	"strconv"
+	"strings"
+)
+
+// semver is a numeric version with up to four components (Composer allows
+// four, npm three) and an optional prerelease tag.
+type semver struct {
+	nums [4]int
+	pre  []string
+}
+
+// partial is a version that may leave trailing components unspecified or
+// wildcarded ("1", "1.2", "1.x", "*"). n is the number of components given
+// and wild is set when a wildcard ended them.
+type partial struct {
+	semver
+	n    int
+	wild bool
+}
+
+// parsePartial parses "v1.2.3-beta.1+build", "1.2", "1.x" or "*".
+func parsePartial(s string) (partial, bool) {
+	s = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s), "="), "v")
+	s = strings.TrimPrefix(s, "V")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+
+	var p partial
+	core, pre, hasPre := strings.Cut(s, "-")
+	if hasPre {
+		p.pre = strings.Split(pre, ".")
+	}
+	if core == "" {
+		return p, false
+	}
+
+	for _, part := range strings.Split(core, ".") {
+		if part == "x" || part == "X" || part == "*" {
+			p.wild = true
+			break
+		}
+		if p.n == len(p.nums) {
+			return p, false
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return p, false
+		}
+		p.nums[p.n] = n
+		p.n++
+	}
+	return p, true
+}
+
+// parseSemver parses a published version; all components must be numbers.
+func parseSemver(s string) (semver, bool) {
+	p, ok := parsePartial(s)
+	return p.semver, ok && p.n > 0
+}
+
+func compareSemver(a, b semver) int {
+	for i := range a.nums {
+		if a.nums[i] != b.nums[i] {
+			if a.nums[i] < b.nums[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	// A prerelease sorts before its release.
+	switch {
+	case len(a.pre) == 0 && len(b.pre) == 0:
+		return 0
+	case len(a.pre) == 0:
+		return 1
+	case len(b.pre) == 0:
+		return -1
+	}
+	for i := 0; i < len(a.pre) && i < len(b.pre); i++ {
+		if c := compareIdentifier(a.pre[i], b.pre[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInt(len(a.pre), len(b.pre))
+}
+
+// compareIdentifier compares prerelease identifiers: numeric ones
+// numerically and before alphanumeric ones, those lexically.
+func compareIdentifier(a, b string) int {
+	an, aErr := strconv.Atoi(a)
+	bn, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return compareInt(an, bn)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// comparator is a single bound such as ">=1.2.0".
+type comparator struct {
+	op string
+	v  semver
+}
+
+func (c comparator) matches(v semver) bool {
+	cmp := compareSemver(v, c.v)
+	switch c.op {
+	case ">=":
+		return cmp >= 0
+	case ">":
+		return cmp > 0
+	case "<=":
+		return cmp <= 0
+	case "<":
+		return cmp < 0
+	case "!=":
+		return cmp != 0
+	}
+	return cmp == 0
+}
+
+// bump returns the smallest version above every version starting with the
+// first n components of p: bump(1.2.3, 2) is 1.3.0.
+func bump(p partial, n int) semver {
+	var v semver
+	copy(v.nums[:], p.nums[:n])
+	if n > 0 {
+		v.nums[n-1]++
+	}
+	// Below every prerelease of that version, so <2.0.0 excludes 2.0.0-rc.
+	v.pre = []string{"0"}
+	return v
+}
+
+// wildcardRange returns the bounds of a partial version used as a range:
+// "1.2" and "1.2.x" are >=1.2.0 <1.3.0, "1.2.3.*" is >=1.2.3.0 <1.2.4.0,
+// "*" matches everything, and three or more components without a wildcard
+// name a single version.
+func wildcardRange(p partial) []comparator {
+	switch {
+	case p.n == 0:
+		return nil
+	case p.n >= 3 && !p.wild:
+		return []comparator{{"=", p.semver}}
+	}
+	return []comparator{{">=", p.semver}, {"<", bump(p, p.n)}}
+}
+
+// caretRange is ^p: changes that do not modify the left-most non-zero
+// component. When every given component is zero the last one may not
+// change: ^0 is <1.0.0, ^0.0 is <0.1.0.
+func caretRange(p partial) []comparator {
+	if p.n == 0 {
+		return nil
+	}
+	n := 0
+	for n < p.n && p.nums[n] == 0 {
+		n++
+	}
+	if n < p.n {
+		n++
+	}
+	return []comparator{{">=", p.semver}, {"<", bump(p, n)}}
+}
+
+// hyphenRange is "lo - hi": a partial upper bound includes every version
+// starting with it, so "1.2 - 2" is >=1.2.0 <3.0.0.
+func hyphenRange(lo, hi string) ([]comparator, bool) {
+	low, lowOK := parsePartial(lo)
+	high, highOK := parsePartial(hi)
+	if !lowOK || !highOK {
+		return nil, false
+	}
+	set := []comparator{{">=", low.semver}}
+	switch {
+	case high.n >= 3:
+		set = append(set, comparator{"<=", high.semver})
+	case high.n > 0:
+		set = append(set, comparator{"<", bump(high, high.n)})
+	}
+	return set, true
+}
+
+// matchAll reports whether v satisfies every comparator.
+func matchAll(set []comparator, v semver) bool {
+	for _, c := range set {
+		if !c.matches(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// satisfiable reports whether any published version satisfies any of the
+// comparator sets.
+func satisfiable(sets [][]comparator, published []string) bool {
+	for _, raw := range published {
+		v, ok := parseSemver(raw)
+		if !ok {
+			continue
+		}
+		for _, set := range sets {
+			if matchAll(set, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// splitOperator splits a comparator like ">=1.2" into its operator and
+// version.
+func splitOperator(s string) (string, string) {
+	for _, op := range []string{">=", "<=", "!=", "==", "<>", ">", "<", "=", "^", "~"} {
+		if rest, ok := strings.CutPrefix(s, op); ok {
+			return op, strings.TrimSpace(rest)
+		}
+	}
+	return "", s
+}
+
+// joinOperators merges operators separated from their version by spaces,
+// as in ">= 1.2".
+func joinOperators(fields []string) []string {
+	var joined []string
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		if op, rest := splitOperator(f); op != "" && rest == "" && i+1 < len(fields) {
+			i++
+			f += fields[i]
+		}
+		joined = append(joined, f)
+	}
+	return joined
+}